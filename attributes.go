@@ -0,0 +1,134 @@
+package aureus
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/dogmatiq/aureus/internal/test"
+)
+
+// AttributeSpec describes an attribute that is allowed to appear on a test's
+// input or expected output. See [AttributeSchema].
+type AttributeSpec struct {
+	key      string
+	validate func(string) error
+}
+
+// StringAttribute returns an [AttributeSpec] for an attribute that may have
+// any value.
+func StringAttribute(key string) AttributeSpec {
+	return AttributeSpec{
+		key,
+		func(string) error { return nil },
+	}
+}
+
+// IntAttribute returns an [AttributeSpec] for an attribute with an integer
+// value. See [Attributes.Int].
+func IntAttribute(key string) AttributeSpec {
+	return AttributeSpec{
+		key,
+		func(v string) error {
+			_, err := test.ParseIntAttribute(v)
+			return err
+		},
+	}
+}
+
+// BoolAttribute returns an [AttributeSpec] for an attribute with a boolean
+// value. See [Attributes.Bool].
+func BoolAttribute(key string) AttributeSpec {
+	return AttributeSpec{
+		key,
+		func(v string) error {
+			_, err := test.ParseBoolAttribute(v)
+			return err
+		},
+	}
+}
+
+// DurationAttribute returns an [AttributeSpec] for an attribute with a
+// [time.Duration] value. See [Attributes.Duration].
+func DurationAttribute(key string) AttributeSpec {
+	return AttributeSpec{
+		key,
+		func(v string) error {
+			_, err := test.ParseDurationAttribute(v)
+			return err
+		},
+	}
+}
+
+// EnumAttribute returns an [AttributeSpec] for an attribute that must have one
+// of the given values. See [Attributes.Enum].
+func EnumAttribute(key string, values ...string) AttributeSpec {
+	return AttributeSpec{
+		key,
+		func(v string) error {
+			return test.ParseEnumAttribute(v, values)
+		},
+	}
+}
+
+// AttributeSchema is a [RunOption] that declares the attributes that may
+// appear on test inputs and outputs.
+//
+// When a schema is declared, loading fails if any input, output, fixture or
+// round-trip content has an attribute that is not described by the schema, or
+// has a value that is not valid for that attribute's type. This includes attributes that are added by
+// loaders, such as the "variant" attribute used by [VariantLayout].
+//
+// It may be used multiple times to add to the schema.
+func AttributeSchema(specs ...AttributeSpec) RunOption {
+	return func(o *runOptions) {
+		if o.AttributeSchema == nil {
+			o.AttributeSchema = map[string]AttributeSpec{}
+		}
+
+		for _, s := range specs {
+			o.AttributeSchema[s.key] = s
+		}
+	}
+}
+
+// schemaValidator is a [test.Visitor] that validates the attributes of all of
+// the content used by each assertion against a schema.
+type schemaValidator map[string]AttributeSpec
+
+func (v schemaValidator) VisitTest([]string, test.Test) error {
+	return nil
+}
+
+func (v schemaValidator) VisitAssertion(_ []string, a test.Assertion) error {
+	content := []test.Content{a.Input, a.Output}
+	content = append(content, a.NamedInputs...)
+	content = append(content, a.Fixtures...)
+	if a.RoundTrip != nil {
+		content = append(content, *a.RoundTrip)
+	}
+
+	for _, c := range content {
+		if err := v.validate(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v schemaValidator) validate(c test.Content) error {
+	for _, k := range slices.Sorted(maps.Keys(c.Attributes)) {
+		value := c.Attributes[k]
+		spec, ok := v[k]
+		if !ok {
+			return fmt.Errorf("%s: unrecognized attribute %q", c.Location(), k)
+		}
+
+		if err := spec.validate(value); err != nil {
+			return fmt.Errorf("%s: %q attribute: %w", c.Location(), k, err)
+		}
+	}
+
+	return nil
+}
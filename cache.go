@@ -0,0 +1,83 @@
+package aureus
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// resultCache holds the results of an operation that produces several outputs
+// at once, such as running a program, so that the expected outputs that share
+// the same input are compared to the same result.
+//
+// A result is held for each input, as the assertions for the outputs that
+// share an input are not necessarily made consecutively.
+type resultCache[R any] struct {
+	m       sync.Mutex
+	entries map[string]*resultCacheEntry[R]
+}
+
+type resultCacheEntry[R any] struct {
+	result   R
+	consumed map[string]struct{}
+}
+
+// get returns the result of the operation for the input identified by key,
+// calling op to produce it if there is no cached result for that input.
+//
+// output is the name of the output that is being generated. The operation is
+// performed again if output has already used the cached result, as happens
+// when the output is generated repeatedly, such as by [Repeat].
+func (c *resultCache[R]) get(key, output string, op func() (R, error)) (R, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	e, ok := c.entries[key]
+	if ok {
+		_, consumed := e.consumed[output]
+		ok = !consumed
+	}
+
+	if !ok {
+		r, err := op()
+		if err != nil {
+			delete(c.entries, key)
+			var zero R
+			return zero, err
+		}
+
+		if c.entries == nil {
+			c.entries = map[string]*resultCacheEntry[R]{}
+		}
+
+		e = &resultCacheEntry[R]{
+			result:   r,
+			consumed: map[string]struct{}{},
+		}
+		c.entries[key] = e
+	}
+
+	e.consumed[output] = struct{}{}
+	return e.result, nil
+}
+
+// inputKey returns a string that identifies an input with the given location,
+// attributes and data, for use as a [resultCache] key.
+func inputKey(loc Location, attrs Attributes, data []byte) string {
+	var w strings.Builder
+
+	w.WriteString(loc.String())
+	w.WriteByte(0)
+
+	for _, k := range slices.Sorted(maps.Keys(attrs)) {
+		w.WriteString(k)
+		w.WriteByte('=')
+		w.WriteString(attrs[k])
+		w.WriteByte(0)
+	}
+
+	w.Write(data)
+
+	return w.String()
+}
@@ -0,0 +1,133 @@
+package aureus
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/dogmatiq/aureus/internal/test"
+)
+
+// Case is a test case that is defined in Go code, rather than loaded from a
+// file. The expected output is stored in a "golden" file.
+type Case struct {
+	// Name is the name of the test.
+	Name string
+
+	// Input is the input to the test.
+	Input []byte
+
+	// InputLanguage is the language of the input, if known, e.g. "json",
+	// "yaml", etc.
+	InputLanguage string
+
+	// Golden is the path to the file that contains the expected output,
+	// relative to the directory that is searched for tests.
+	//
+	// If the file does not exist the expected output is empty, and blessing
+	// the test creates the file. The file name should not match the naming
+	// conventions used for flat-file tests.
+	Golden string
+
+	// OutputLanguage is the language of the expected output, if known. If it
+	// is empty, the file extension of Golden is used.
+	OutputLanguage string
+
+	// Attributes is a set of key-value pairs that provide additional
+	// information about the test to the [OutputGenerator].
+	Attributes map[string]string
+
+	// Skip indicates that the test should be skipped.
+	Skip bool
+}
+
+// Cases is a [RunOption] that adds test cases defined in Go code, in addition
+// to those loaded from files.
+//
+// The tests are merged with the tests loaded from the directory that is
+// searched for tests, such that they appear alongside the tests loaded from
+// files at the top-level of that directory.
+func Cases(cases ...Case) RunOption {
+	return func(o *runOptions) {
+		o.Loaders = append(o.Loaders, caseLoader(cases))
+	}
+}
+
+// caseLoader is a [Loader] that builds tests from [Case] values.
+type caseLoader []Case
+
+func (l caseLoader) Load(fsys fs.FS, dir string) (Test, error) {
+	var tests []test.Test
+
+	for _, c := range l {
+		t, err := c.build(fsys, dir)
+		if err != nil {
+			return test.Test{}, err
+		}
+		tests = append(tests, t)
+	}
+
+	name, skip := strings.CutPrefix(path.Base(dir), "_")
+
+	return test.New(
+		name,
+		test.WithSkip(skip),
+		test.WithSubTests(tests...),
+	), nil
+}
+
+// location returns a synthetic file name that identifies the input of c in
+// messages, as it is not loaded from a file.
+func (c Case) location() string {
+	return fmt.Sprintf("case %q", c.Name)
+}
+
+// build returns the test for c.
+func (c Case) build(fsys fs.FS, dir string) (test.Test, error) {
+	if c.Name == "" {
+		return test.Test{}, errors.New("test case must have a name")
+	}
+
+	if c.Golden == "" {
+		return test.Test{}, fmt.Errorf("test case %q must specify a golden file", c.Name)
+	}
+
+	golden := path.Join(dir, c.Golden)
+
+	data, err := fs.ReadFile(fsys, golden)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return test.Test{}, fmt.Errorf("test case %q: %w", c.Name, err)
+	}
+
+	lang := c.OutputLanguage
+	if lang == "" {
+		lang = strings.TrimPrefix(path.Ext(golden), ".")
+	}
+
+	return test.New(
+		c.Name,
+		test.WithSkip(c.Skip),
+		test.WithAssertions(
+			test.Assertion{
+				Input: test.Content{
+					ContentMetaData: test.ContentMetaData{
+						File:       c.location(),
+						Language:   c.InputLanguage,
+						Attributes: c.Attributes,
+					},
+					Data: c.Input,
+				},
+				Output: test.Content{
+					ContentMetaData: test.ContentMetaData{
+						File:       golden,
+						Language:   lang,
+						Attributes: c.Attributes,
+					},
+					Data: data,
+				},
+			},
+		),
+	), nil
+}
@@ -0,0 +1,170 @@
+package aureus
+
+import (
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Codec encodes and decodes values of a specific language, such as JSON.
+type Codec interface {
+	// Decode reads a value from r and stores it in the value pointed to by v.
+	Decode(r io.Reader, v any) error
+
+	// Encode writes the representation of v to w.
+	Encode(w io.Writer, v any) error
+}
+
+var (
+	codecsM sync.RWMutex
+	codecs  = map[string]Codec{
+		"json": jsonCodec{},
+		"xml":  xmlCodec{},
+		"":     textCodec{},
+		"text": textCodec{},
+		"txt":  textCodec{},
+	}
+)
+
+// RegisterCodec registers the codec to use for inputs and outputs with the
+// given language, replacing any existing codec for that language.
+//
+// Codecs for JSON ("json"), XML ("xml") and plain text ("", "text" and "txt")
+// are registered by default.
+func RegisterCodec(lang string, c Codec) {
+	codecsM.Lock()
+	defer codecsM.Unlock()
+	codecs[lang] = c
+}
+
+// codecFor returns the codec registered for the given language.
+func codecFor(lang string) (Codec, error) {
+	codecsM.RLock()
+	defer codecsM.RUnlock()
+
+	if c, ok := codecs[lang]; ok {
+		return c, nil
+	}
+
+	return nil, fmt.Errorf("no codec is registered for the %q language", lang)
+}
+
+// Func returns an [OutputGenerator] that decodes the input, passes it to fn,
+// and encodes the result as the output.
+//
+// The input and output are decoded and encoded using the [Codec] registered for
+// their respective languages. See [RegisterCodec].
+func Func[T TestingT[T], In, Out any](fn func(In) (Out, error)) OutputGenerator[T] {
+	return func(t T, in Input, out Output) error {
+		t.Helper()
+
+		dec, err := codecFor(in.Language())
+		if err != nil {
+			return err
+		}
+
+		enc, err := codecFor(out.Language())
+		if err != nil {
+			return err
+		}
+
+		var v In
+		if err := dec.Decode(in, &v); err != nil {
+			return fmt.Errorf("unable to decode input: %w", err)
+		}
+
+		result, err := fn(v)
+		if err != nil {
+			return err
+		}
+
+		if err := enc.Encode(out, result); err != nil {
+			return fmt.Errorf("unable to encode output: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// jsonCodec is a [Codec] for JSON. It encodes values with two-space
+// indentation.
+type jsonCodec struct{}
+
+func (jsonCodec) Decode(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+func (jsonCodec) Encode(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// xmlCodec is a [Codec] for XML. It encodes values with two-space indentation.
+type xmlCodec struct{}
+
+func (xmlCodec) Decode(r io.Reader, v any) error {
+	return xml.NewDecoder(r).Decode(v)
+}
+
+func (xmlCodec) Encode(w io.Writer, v any) error {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// textCodec is a [Codec] for plain text.
+//
+// It decodes into strings, byte slices and [encoding.TextUnmarshaler]
+// implementations. It encodes strings, byte slices and
+// [encoding.TextMarshaler] implementations verbatim, and any other value using
+// [fmt.Fprint].
+type textCodec struct{}
+
+func (textCodec) Decode(r io.Reader, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case *string:
+		*v = string(data)
+	case *[]byte:
+		*v = data
+	case encoding.TextUnmarshaler:
+		return v.UnmarshalText(data)
+	default:
+		return fmt.Errorf("unable to decode text into %T", v)
+	}
+
+	return nil
+}
+
+func (textCodec) Encode(w io.Writer, v any) error {
+	switch v := v.(type) {
+	case string:
+		_, err := io.WriteString(w, v)
+		return err
+	case []byte:
+		_, err := w.Write(v)
+		return err
+	case encoding.TextMarshaler:
+		data, err := v.MarshalText()
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	default:
+		_, err := fmt.Fprint(w, v)
+		return err
+	}
+}
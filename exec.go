@@ -0,0 +1,248 @@
+package aureus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Names of the outputs produced by [Exec] and [ExecCommand].
+const (
+	StdoutOutput   = "stdout"
+	StderrOutput   = "stderr"
+	ExitCodeOutput = "exit-code"
+)
+
+// Command describes an external program that is executed by [ExecCommand].
+type Command struct {
+	// Path is the name or path of the program to execute. If it does not
+	// contain a path separator it is located using [exec.LookPath].
+	Path string
+
+	// Args is the list of arguments passed to the program.
+	//
+	// Each argument is a [text/template] that is executed with the test's
+	// attributes as its data, such as "--width={{.width}}".
+	Args []string
+
+	// Env is a list of additional environment variables, in "KEY=value" form.
+	Env []string
+
+	// AttributeFlags, if true, passes each of the test's attributes to the
+	// program as a flag of the form "--key=value", or "--key" if the attribute
+	// has no value, after the arguments in Args.
+	AttributeFlags bool
+
+	// Timeout is the maximum amount of time that the program may run. If it is
+	// zero there is no limit.
+	Timeout time.Duration
+}
+
+// Exec returns an [OutputGenerator] that runs an external program. It is
+// equivalent to calling [ExecCommand] with a [Command] that has the given path
+// and arguments.
+func Exec[T TestingT[T]](path string, args ...string) OutputGenerator[T] {
+	return ExecCommand[T](
+		Command{
+			Path: path,
+			Args: args,
+		},
+	)
+}
+
+// ExecCommand returns an [OutputGenerator] that runs an external program.
+//
+// The program is executed within a temporary working directory, with the
+// test's input on its standard input. The attributes of the input and expected
+// output are made available as environment variables named
+// "AUREUS_<KEY>", where <KEY> is the upper-case attribute key.
+//
+// The output that is produced depends on the name of the expected output:
+//
+//   - [StdoutOutput], or an un-named output, produces the program's standard
+//     output
+//   - [StderrOutput] produces the program's standard error
+//   - [ExitCodeOutput] produces the program's exit code
+//
+// The program is run once for each input, and each of the expected outputs
+// that share that input are compared to the result of the same run. A non-zero
+// exit code is not treated as a failure, allowing failures to be tested using
+// the [ExitCodeOutput] output.
+func ExecCommand[T TestingT[T]](cmd Command) OutputGenerator[T] {
+	var cache resultCache[execResult]
+
+	return func(t T, in Input, out Output) error {
+		t.Helper()
+
+		attrs := maps.Clone(in.Attributes())
+		if attrs == nil {
+			attrs = Attributes{}
+		}
+		maps.Copy(attrs, out.Attributes())
+
+		stdin, err := io.ReadAll(in)
+		if err != nil {
+			return err
+		}
+
+		res, err := cache.get(
+			inputKey(in.Location(), attrs, stdin),
+			out.Name(),
+			func() (execResult, error) {
+				return cmd.run(attrs, stdin)
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		switch out.Name() {
+		case "", StdoutOutput:
+			_, err = out.Write(res.Stdout)
+		case StderrOutput:
+			_, err = out.Write(res.Stderr)
+		case ExitCodeOutput:
+			_, err = fmt.Fprintf(out, "%d\n", res.ExitCode)
+		default:
+			return fmt.Errorf(
+				"unrecognized output name %q, expected %q, %q or %q",
+				out.Name(),
+				StdoutOutput,
+				StderrOutput,
+				ExitCodeOutput,
+			)
+		}
+
+		return err
+	}
+}
+
+// execResult is the result of running a [Command].
+type execResult struct {
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+}
+
+// run runs the program for a test with the given attributes, within a
+// temporary working directory.
+func (cmd Command) run(attrs Attributes, stdin []byte) (execResult, error) {
+	ctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if cmd.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, cmd.Timeout)
+	}
+	defer cancel()
+
+	c, err := cmd.build(ctx, attrs)
+	if err != nil {
+		return execResult{}, err
+	}
+
+	dir, err := os.MkdirTemp("", "aureus-exec-")
+	if err != nil {
+		return execResult{}, fmt.Errorf("unable to create temporary working directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var stdout, stderr bytes.Buffer
+	c.Dir = dir
+	c.Stdin = bytes.NewReader(stdin)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	exitCode := 0
+	if err := c.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return execResult{}, fmt.Errorf("%s timed out after %s", cmd.Path, cmd.Timeout)
+		}
+
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || c.ProcessState == nil || !c.ProcessState.Exited() {
+			return execResult{}, fmt.Errorf("unable to run %s: %w", cmd.Path, err)
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	return execResult{
+		Stdout:   stdout.Bytes(),
+		Stderr:   stderr.Bytes(),
+		ExitCode: exitCode,
+	}, nil
+}
+
+// build returns the [exec.Cmd] to run for a test with the given attributes.
+func (cmd Command) build(ctx context.Context, attrs Attributes) (*exec.Cmd, error) {
+	path := cmd.Path
+	if strings.ContainsRune(path, filepath.Separator) || strings.ContainsRune(path, '/') {
+		// Resolve relative paths before the working directory is changed.
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		path = abs
+	}
+
+	var args []string
+
+	for _, arg := range cmd.Args {
+		tmpl, err := template.New("arg").Option("missingkey=error").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument template %q: %w", arg, err)
+		}
+
+		var w strings.Builder
+		if err := tmpl.Execute(&w, map[string]string(attrs)); err != nil {
+			return nil, fmt.Errorf("unable to render argument template %q: %w", arg, err)
+		}
+
+		args = append(args, w.String())
+	}
+
+	keys := slices.Sorted(maps.Keys(attrs))
+
+	if cmd.AttributeFlags {
+		for _, k := range keys {
+			if v := attrs[k]; v == "" {
+				args = append(args, "--"+k)
+			} else {
+				args = append(args, "--"+k+"="+v)
+			}
+		}
+	}
+
+	c := exec.CommandContext(ctx, path, args...)
+	c.Env = append(os.Environ(), cmd.Env...)
+
+	for _, k := range keys {
+		c.Env = append(c.Env, attributeEnvName(k)+"="+attrs[k])
+	}
+
+	return c, nil
+}
+
+// attributeEnvName returns the name of the environment variable used to pass
+// the attribute with the given key to an external program.
+func attributeEnvName(k string) string {
+	var w strings.Builder
+	w.WriteString("AUREUS_")
+
+	for _, r := range strings.ToUpper(k) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			w.WriteRune(r)
+		} else {
+			w.WriteByte('_')
+		}
+	}
+
+	return w.String()
+}
@@ -1,12 +1,67 @@
 package aureus
 
 import (
+	"fmt"
 	"io"
+	"iter"
+
+	"github.com/dogmatiq/aureus/internal/runner"
+	"github.com/dogmatiq/aureus/internal/test"
 )
 
+// Attributes is a set of key-value pairs that provide additional
+// loader-specific information about a test's input or expected output.
+//
+// In addition to being used directly as a map, it provides methods for
+// parsing attribute values.
+type Attributes = test.Attributes
+
+// Fixture is additional content that provides context for a test's input.
+type Fixture = runner.Fixture
+
+// Location describes where a test's input or expected output was loaded from.
+type Location = test.Location
+
 // OutputGenerator produces the output of a specific test.
 type OutputGenerator[T TestingT[T]] func(T, Input, Output) error
 
+// RoundTrip returns an [OutputGenerator] that checks that the output of each
+// test can be converted back into its input.
+//
+// forward produces the output of each test, as per the generator passed to
+// [Run]. backward is its inverse. It is called with the actual output of each
+// assertion as its input. Its output is compared to the assertion's original
+// input, or to the assertion's explicit round-trip content, if present.
+//
+// The returned generator is passed to [Run] in place of forward, such as:
+//
+//	aureus.Run(t, aureus.RoundTrip(parse, print))
+//
+// It must be called with the [Output] that is passed to the generator by [Run],
+// otherwise it returns an error. Unlike a [RunOption], this allows the compiler
+// to check that both generators accept the same test type as [Run].
+func RoundTrip[T TestingT[T]](
+	forward, backward OutputGenerator[T],
+) OutputGenerator[T] {
+	return func(t T, in Input, out Output) error {
+		o, ok := out.(runner.RoundTripOutput[T])
+		if !ok {
+			return fmt.Errorf(
+				"round-trip is not supported by %T, which is not the output passed by Run()",
+				out,
+			)
+		}
+
+		o.SetRoundTrip(
+			func(t T, in runner.Input, out runner.Output) error {
+				return backward(t, input{in}, out)
+			},
+		)
+
+		return forward(t, in, out)
+	}
+}
+
 // Input is an interface for the input to a test.
 type Input interface {
 	io.Reader
@@ -17,7 +72,45 @@ type Input interface {
 
 	// Attributes returns a set of key-value pairs that provide additional
 	// loader-specific information about the input.
-	Attributes() map[string]string
+	Attributes() Attributes
+
+	// Location returns the location of the input within the file from which
+	// it was loaded.
+	Location() Location
+
+	// Group returns the name of the group to which the input belongs, or an
+	// empty string if it does not belong to a named group.
+	Group() string
+
+	// Caption returns an optional disambiguating name, title or short
+	// description of the input.
+	Caption() string
+
+	// Headings returns the document headings under which the input appears,
+	// outermost first. It returns nil if the loader does not support headings.
+	Headings() []string
+
+	// TestName returns the full name of the Go test that is making the
+	// assertion, as per [testing.T.Name].
+	TestName() string
+
+	// Fixtures returns the fixtures that provide additional context for the
+	// input, such as a schema or configuration file.
+	Fixtures() []Fixture
+
+	// Name returns the name of the input, if any.
+	Name() string
+
+	// Named returns the named input with the given name. Named inputs are
+	// used together within a single test, such as the "old" and "new" inputs
+	// of a migration.
+	//
+	// ok is false if the test has no input with the given name.
+	Named(name string) (_ Input, ok bool)
+
+	// NamedInputs returns an iterator over the test's named inputs, in the
+	// order that they were loaded.
+	NamedInputs() iter.Seq2[string, Input]
 }
 
 // Output is an interface for producing the output for a test.
@@ -28,7 +121,61 @@ type Output interface {
 	// e.g. "json", "yaml", etc.
 	Language() string
 
+	// Name returns the name of the output, if any. Names distinguish between
+	// several outputs produced from the same input, such as "stdout" and
+	// "stderr".
+	Name() string
+
+	// Stage returns the name of the pipeline stage that produces the output,
+	// if any. Within a pipeline, the input to each stage is the actual output
+	// of the previous stage.
+	Stage() string
+
 	// Attributes returns a set of key-value pairs that provide additional
 	// loader-specific information about the expected output.
-	Attributes() map[string]string
+	Attributes() Attributes
+
+	// Location returns the location of the expected output within the file from
+	// which it was loaded.
+	Location() Location
+
+	// Group returns the name of the group to which the expected output belongs,
+	// or an empty string if it does not belong to a named group.
+	Group() string
+
+	// Caption returns an optional disambiguating name, title or short
+	// description of the expected output.
+	Caption() string
+
+	// Headings returns the document headings under which the expected output
+	// appears, outermost first. It returns nil if the loader does not support
+	// headings.
+	Headings() []string
+
+	// TestName returns the full name of the Go test that is making the
+	// assertion, as per [testing.T.Name].
+	TestName() string
+}
+
+// input adapts a [runner.Input] to the [Input] interface.
+type input struct {
+	runner.Input
+}
+
+func (i input) Named(name string) (Input, bool) {
+	x, ok := i.Input.Named(name)
+	if !ok {
+		return nil, false
+	}
+	return input{x}, true
+}
+
+func (i input) NamedInputs() iter.Seq2[string, Input] {
+	return func(yield func(string, Input) bool) {
+		for n, x := range i.Input.NamedInputs() {
+			if !yield(n, input{x}) {
+				return
+			}
+		}
+	}
 }
@@ -0,0 +1,183 @@
+package aureus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"slices"
+	"strings"
+)
+
+// HTTPOption is an option that changes the behavior of [HTTPHandler].
+type HTTPOption func(*httpOptions)
+
+type httpOptions struct {
+	ExcludeHeaders []string
+	Formatters     map[string]func([]byte) ([]byte, error)
+	IndentJSON     bool
+}
+
+// HTTPExcludeHeaders is an [HTTPOption] that omits the given response headers
+// from the output, such as those with values that change each time the test is
+// run.
+func HTTPExcludeHeaders(names ...string) HTTPOption {
+	return func(o *httpOptions) {
+		for _, n := range names {
+			o.ExcludeHeaders = append(o.ExcludeHeaders, textproto.CanonicalMIMEHeaderKey(n))
+		}
+	}
+}
+
+// HTTPFormatBody is an [HTTPOption] that formats response bodies with the
+// given media type, such as "application/json", using fn.
+func HTTPFormatBody(mediaType string, fn func([]byte) ([]byte, error)) HTTPOption {
+	return func(o *httpOptions) {
+		if o.Formatters == nil {
+			o.Formatters = map[string]func([]byte) ([]byte, error){}
+		}
+		o.Formatters[mediaType] = fn
+	}
+}
+
+// HTTPIndentJSON is an [HTTPOption] that sets whether JSON response bodies are
+// indented. It applies to "application/json" and any "+json" media type that
+// does not have a formatter configured by [HTTPFormatBody].
+func HTTPIndentJSON(on bool) HTTPOption {
+	return func(o *httpOptions) {
+		o.IndentJSON = on
+	}
+}
+
+// HTTPHandler returns an [OutputGenerator] that tests an [http.Handler].
+//
+// The input is a raw HTTP/1.1 request, which is typically given the "http"
+// language. If the request has no Content-Length header, any content after the
+// headers is used as the request body.
+//
+// The output is the response produced by h, consisting of the status line, the
+// response headers sorted by name, and the body.
+func HTTPHandler[T TestingT[T]](h http.Handler, options ...HTTPOption) OutputGenerator[T] {
+	var opts httpOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	return func(t T, in Input, out Output) error {
+		t.Helper()
+
+		req, err := readHTTPRequest(in)
+		if err != nil {
+			return err
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		return writeHTTPResponse(out, rec.Result(), opts)
+	}
+}
+
+// readHTTPRequest parses a raw HTTP/1.1 request.
+func readHTTPRequest(r io.Reader) (*http.Request, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure that the headers are terminated by a blank line, which is often
+	// omitted from requests that have no body.
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+	if !bytes.Contains(data, []byte("\n\n")) && !bytes.Contains(data, []byte("\r\n\r\n")) {
+		data = append(data, '\n')
+	}
+
+	buf := bufio.NewReader(bytes.NewReader(data))
+
+	req, err := http.ReadRequest(buf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse HTTP request: %w", err)
+	}
+
+	if req.ContentLength == 0 && len(req.TransferEncoding) == 0 {
+		body, err := io.ReadAll(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(body) != 0 {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			req.ContentLength = int64(len(body))
+		}
+	}
+
+	return req, nil
+}
+
+// writeHTTPResponse writes a human-readable representation of res to w.
+func writeHTTPResponse(w io.Writer, res *http.Response, opts httpOptions) error {
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+
+	fn := opts.Formatters[mediaType]
+	if fn == nil && opts.IndentJSON && isJSONMediaType(mediaType) {
+		fn = indentJSON
+	}
+
+	if fn != nil && len(body) != 0 {
+		body, err = fn(body)
+		if err != nil {
+			return fmt.Errorf("unable to format %s response body: %w", mediaType, err)
+		}
+	}
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "%s %s\n", res.Proto, res.Status)
+
+	for _, k := range slices.Sorted(maps.Keys(res.Header)) {
+		if slices.Contains(opts.ExcludeHeaders, k) {
+			continue
+		}
+
+		for _, v := range res.Header[k] {
+			fmt.Fprintf(&buf, "%s: %s\n", k, v)
+		}
+	}
+
+	if len(body) != 0 {
+		buf.WriteString("\n")
+		buf.Write(body)
+
+		if !bytes.HasSuffix(body, []byte("\n")) {
+			buf.WriteString("\n")
+		}
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// indentJSON indents a JSON body using two spaces.
+func indentJSON(data []byte) ([]byte, error) {
+	var w bytes.Buffer
+	err := json.Indent(&w, data, "", "  ")
+	return w.Bytes(), err
+}
+
+// isJSONMediaType returns true if mediaType is a JSON media type.
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
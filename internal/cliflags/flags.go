@@ -6,8 +6,9 @@ import (
 
 // Flags is a struct that holds all Aureus command-line flags.
 type Flags struct {
-	Bless bool
-	Lang  string
+	Bless  bool
+	Lang   string
+	Repeat int
 }
 
 // Get returns the Aureus command-line flags.
@@ -31,4 +32,11 @@ func init() {
 		"",
 		"only execute tests that have an input or output in the specified language",
 	)
+
+	flag.IntVar(
+		&flags.Repeat,
+		"aureus.repeat",
+		0,
+		"generate the output of each assertion the specified number of times, failing if the outputs differ",
+	)
 }
@@ -14,14 +14,22 @@ import (
 // TestBuilder builds [test.Test] values from groups of correlated inputs and
 // outputs.
 type TestBuilder struct {
-	tests  []test.Test
-	groups map[string]*group
-	anon   ContentEnvelope
+	tests    []test.Test
+	groups   map[string]*group
+	anon     ContentEnvelope
+	fixtures []ContentEnvelope
 }
 
 type group struct {
 	Name            string
 	Inputs, Outputs []ContentEnvelope
+	Fixtures        []ContentEnvelope
+	RoundTrips      []ContentEnvelope
+
+	// NamedInputs is the set of named inputs that have already been separated
+	// from the group's other inputs by [buildNamedInputTest], or nil if they
+	// have not yet been separated.
+	NamedInputs []ContentEnvelope
 }
 
 // AddTest adds a pre-built test to the builder.
@@ -37,8 +45,22 @@ func (b *TestBuilder) AddTest(t test.Test) {
 //
 // Content with no [Role] is ignored.
 func (b *TestBuilder) AddContent(env ContentEnvelope) error {
-	if env.Content.Role == NoRole {
+	switch env.Content.Role {
+	case NoRole:
 		return nil
+	case Fixture:
+		b.addFixture(env)
+		return nil
+	case RoundTrip:
+		return b.addRoundTrip(env)
+	}
+
+	if env.Content.Role == Input && !env.Content.Platform.IsZero() {
+		return fmt.Errorf(
+			"input loaded from %s must not have platform constraints (%s)",
+			location(env, true),
+			env.Content.Platform,
+		)
 	}
 
 	if env.Content.Group == nil {
@@ -68,6 +90,40 @@ func (b *TestBuilder) addContent(env ContentEnvelope) error {
 	return nil
 }
 
+// addFixture adds a fixture to its group, or to the builder itself if it does
+// not belong to a named group.
+func (b *TestBuilder) addFixture(env ContentEnvelope) {
+	if env.Content.Group == nil || !env.Content.Group.IsNamed() {
+		b.fixtures = append(b.fixtures, env)
+		return
+	}
+
+	g := b.group(env.Content.Group.Name())
+	g.Fixtures = append(g.Fixtures, env)
+}
+
+// addRoundTrip adds explicit round-trip content to its group.
+func (b *TestBuilder) addRoundTrip(env ContentEnvelope) error {
+	if env.Content.Group == nil {
+		return fmt.Errorf(
+			"round-trip content loaded from %s must belong to a group",
+			location(env, true),
+		)
+	}
+
+	g := b.group(groupName(env.Content.Group))
+	if len(g.RoundTrips) != 0 {
+		return fmt.Errorf(
+			"round-trip content loaded from %s is in the same group as the round-trip content loaded from %s",
+			location(env, true),
+			location(g.RoundTrips[0], true),
+		)
+	}
+
+	g.RoundTrips = append(g.RoundTrips, env)
+	return nil
+}
+
 func (b *TestBuilder) addAnonymousContent(env ContentEnvelope) error {
 	emit := func(in, out ContentEnvelope) {
 		name := fmt.Sprintf("anonymous test on line %d", out.Line)
@@ -100,16 +156,45 @@ func (b *TestBuilder) addAnonymousContent(env ContentEnvelope) error {
 }
 
 // Build returns tests built from the inputs and outputs, sorted by name.
+//
+// Fixtures that belong to a named group are attached to each assertion built
+// from that group. Fixtures that do not belong to a named group are attached to
+// every assertion, including those within tests added by [TestBuilder.AddTest].
 func (b *TestBuilder) Build() ([]test.Test, error) {
 	tests := make([]test.Test, 0, len(b.groups)+len(b.tests))
 	tests = append(tests, b.tests...)
 
+	env := currentEnvironment()
+
 	for _, g := range b.groups {
-		t, err := buildTest(g)
+		if g.IsUnpaired() {
+			if err := g.validateUnpaired(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		outputs, err := selectVariants(g.Outputs, env)
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := buildTest(
+			&group{
+				Name:    g.Name,
+				Inputs:  g.Inputs,
+				Outputs: outputs,
+			},
+		)
 		if err != nil {
 			return nil, err
 		}
-		tests = append(tests, t)
+		t = withRoundTrip(t, g.RoundTrips)
+		tests = append(tests, withFixtures(t, g.Fixtures))
+	}
+
+	for i, t := range tests {
+		tests[i] = withFixtures(t, b.fixtures)
 	}
 
 	slices.SortFunc(
@@ -122,6 +207,34 @@ func (b *TestBuilder) Build() ([]test.Test, error) {
 	return tests, nil
 }
 
+// BuildTest returns a single test with the given name that contains the tests
+// built from the inputs and outputs as sub-tests.
+//
+// If there are inputs and outputs in an unnamed group, the assertions (or
+// sub-tests) they produce belong to the returned test directly, rather than to
+// an un-named sub-test.
+func (b *TestBuilder) BuildTest(name string, options ...test.Option) (test.Test, error) {
+	tests, err := b.Build()
+	if err != nil {
+		return test.Test{}, err
+	}
+
+	t := test.New(name, options...)
+
+	for _, x := range tests {
+		if x.Name != "" {
+			t.SubTests = append(t.SubTests, x)
+			continue
+		}
+
+		t.Skip = t.Skip || x.Skip
+		t.SubTests = append(t.SubTests, x.SubTests...)
+		t.Assertions = append(t.Assertions, x.Assertions...)
+	}
+
+	return t, nil
+}
+
 // LoadDir loads tests from the given directory.
 func LoadDir(
 	fsys fs.FS,
@@ -173,6 +286,113 @@ func LoadDir(
 	), nil
 }
 
+// withRoundTrip returns a copy of t with the given round-trip content attached
+// to each of its assertions, including those within sub-tests.
+func withRoundTrip(t test.Test, roundTrips []ContentEnvelope) test.Test {
+	if len(roundTrips) == 0 {
+		return t
+	}
+
+	t.SubTests = slices.Clone(t.SubTests)
+	for i, s := range t.SubTests {
+		t.SubTests[i] = withRoundTrip(s, roundTrips)
+	}
+
+	c := roundTrips[0].AsTestContent()
+
+	t.Assertions = slices.Clone(t.Assertions)
+	for i := range t.Assertions {
+		t.Assertions[i].RoundTrip = &c
+	}
+
+	return t
+}
+
+// withFixtures returns a copy of t with the given fixtures attached to each of
+// its assertions, including those within sub-tests.
+//
+// The fixtures are placed before any fixtures that are already attached, such
+// that the fixtures from outer groups and directories appear first.
+func withFixtures(t test.Test, fixtures []ContentEnvelope) test.Test {
+	if len(fixtures) == 0 {
+		return t
+	}
+
+	t.SubTests = slices.Clone(t.SubTests)
+	for i, s := range t.SubTests {
+		t.SubTests[i] = withFixtures(s, fixtures)
+	}
+
+	t.Assertions = slices.Clone(t.Assertions)
+	for i, a := range t.Assertions {
+		var x []test.Content
+		for _, f := range fixtures {
+			x = append(x, f.AsTestContent())
+		}
+		a.Fixtures = append(x, a.Fixtures...)
+		t.Assertions[i] = a
+	}
+
+	return t
+}
+
+// IsUnpaired returns true if the group is missing either inputs or outputs,
+// and all of the content that it does have is optional.
+func (g *group) IsUnpaired() bool {
+	if len(g.Inputs) != 0 && len(g.Outputs) != 0 {
+		return false
+	}
+
+	for _, env := range g.Inputs {
+		if !env.Content.Optional {
+			return false
+		}
+	}
+
+	for _, env := range g.Outputs {
+		if !env.Content.Optional {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validateUnpaired returns an error if an unpaired group has fixtures or
+// round-trip content but no inputs or outputs, such that the content is never
+// used. This usually indicates a typo in the group name.
+func (g *group) validateUnpaired() error {
+	if len(g.Inputs) != 0 || len(g.Outputs) != 0 {
+		return nil
+	}
+
+	kind := "fixture"
+	content := g.Fixtures
+	if len(content) == 0 {
+		kind = "round-trip content"
+		content = g.RoundTrips
+	}
+
+	if len(content) == 0 {
+		return nil
+	}
+
+	if g.Name == "" {
+		return fmt.Errorf(
+			"%s loaded from %s is not used by any test, as there are no inputs or outputs without a group",
+			kind,
+			location(content[0], true),
+		)
+	}
+
+	return fmt.Errorf(
+		"%s loaded from %s is not used by any test, as there are no inputs or outputs in the %q group",
+		kind,
+		location(content[0], true),
+		g.Name,
+	)
+}
+
 // group returns the group with the given name, creating it if necessary.
 func (b *TestBuilder) group(name string) *group {
 	if b.groups == nil {
@@ -196,6 +416,12 @@ func buildTest(g *group) (test.Test, error) {
 		return test.Test{}, NoInputsError{g.Outputs}
 	case outputs == 0:
 		return test.Test{}, NoOutputsError{g.Inputs}
+	case g.isPipeline():
+		return buildPipelineTest(g)
+	case g.hasUnseparatedNamedInputs():
+		return buildNamedInputTest(g)
+	case g.hasParameters():
+		return buildParameterizedTest(g)
 	case inputs == 1 && outputs == 1:
 		return buildSingleTest(g), nil
 	case inputs == 1:
@@ -260,6 +486,10 @@ func nameSIMO(input, output ContentEnvelope) string {
 // nameMISO returns a name for a test that has multiple inputs and a single
 // output.
 func nameMISO(input, output ContentEnvelope) string {
+	if input.Content.Name != "" {
+		return input.Content.Name
+	}
+
 	if input.Content.Caption != "" && input.Content.Caption != output.Content.Caption {
 		return input.Content.Caption
 	}
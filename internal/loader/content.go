@@ -17,6 +17,16 @@ const (
 
 	// Output indicates that the content is the expected output from a test.
 	Output
+
+	// Fixture indicates that the content is additional context that is made
+	// available to every test in its group, or to every test built by the same
+	// [TestBuilder] if it does not belong to a named group.
+	Fixture
+
+	// RoundTrip indicates that the content is the expected result of
+	// converting a test's output back into an input, for tests where this
+	// differs from the original input.
+	RoundTrip
 )
 
 // Content is a specialization of [test.Content] that includes meta-data about
@@ -30,10 +40,32 @@ type Content struct {
 	// outputs in the same group form a matrix of test cases.
 	Group *Group
 
+	// Name is an optional name that identifies the content.
+	//
+	// Named inputs are combined into a single assertion, rather than forming a
+	// matrix, such as the "old" and "new" inputs of a migration. Named outputs
+	// distinguish between several outputs produced from the same input, such
+	// as "stdout" and "stderr". Fixtures may also be named.
+	Name string
+
+	// Stage is the name of the pipeline stage that produces the content, if
+	// any. Outputs with stages are chained together, such that the actual
+	// output of each stage is the input to the next.
+	Stage string
+
+	// Optional indicates that the content may be discarded if its group does
+	// not contain any content with the opposite role, rather than causing an
+	// error. For example, an optional input with no outputs is ignored.
+	Optional bool
+
 	// Caption is an optional disambiguating name, title or short description of
 	// the content.
 	Caption string
 
+	// Headings is the list of document headings under which the content
+	// appears, outermost first, if the loader supports headings.
+	Headings []string
+
 	// Language is the language of the content, if known, e.g. "json", "yaml",
 	// etc. Content with an empty language is treated as plain text.
 	Language string
@@ -42,6 +74,22 @@ type Content struct {
 	// loader-specific information about the data.
 	Attributes map[string]string
 
+	// Parameters is a set of list-valued attributes.
+	//
+	// Inputs with parameters are expanded into a separate test for each
+	// combination of parameter values, in which each parameter is replaced by
+	// an attribute with a single value. Each combination is paired with the
+	// outputs in the same group that have the same value for each parameter,
+	// or that do not mention the parameter at all.
+	Parameters map[string][]string
+
+	// Platform describes the environments to which the content applies.
+	//
+	// Outputs that differ only in their platform are variants of each other.
+	// Only the most specific variant that applies to the current environment is
+	// used. Inputs must not have a platform.
+	Platform Platform
+
 	// Data is the content itself.
 	Data []byte
 }
@@ -66,6 +114,10 @@ type ContentEnvelope struct {
 	// running tests.
 	Skip bool
 
+	// Encode is an optional function that converts data into the form in which
+	// it is stored within the file. See [test.ContentMetaData].
+	Encode func([]byte) []byte
+
 	// Content is the loaded content.
 	Content Content
 }
@@ -78,8 +130,14 @@ func (e ContentEnvelope) AsTestContent() test.Content {
 			Line:       e.Line,
 			Begin:      e.Begin,
 			End:        e.End,
+			Name:       e.Content.Name,
+			Stage:      e.Content.Stage,
+			Group:      groupName(e.Content.Group),
+			Caption:    e.Content.Caption,
+			Headings:   e.Content.Headings,
 			Language:   e.Content.Language,
 			Attributes: e.Content.Attributes,
+			Encode:     e.Encode,
 		},
 		Data: e.Content.Data,
 	}
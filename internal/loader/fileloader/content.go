@@ -19,59 +19,48 @@ import (
 type ContentLoader func(name string, f fs.File) (loader.Content, error)
 
 // LoadContent is the default [ContentLoader] implementation.
+//
+// It identifies test files using [loader.ParseFileName].
 func LoadContent(name string, f fs.File) (loader.Content, error) {
-	base := path.Base(name)
-	atoms := strings.Split(base, ".")
-
-	content := loader.Content{
-		// If there is no prefix on the filename before the .input or .output
-		// atom marker, we still want to group the inputs and outputs into a
-		// test matrix.
-		Group: loader.UnnamedGroup(),
+	content := loader.ParseFileName(name)
+	if content.Role == loader.NoRole {
+		return loader.Content{}, nil
 	}
 
-	for idx, atom := range atoms {
-		if strings.EqualFold(atom, "input") {
-			content.Role = loader.Input
-		} else if strings.EqualFold(atom, "output") {
-			content.Role = loader.Output
-		} else {
-			continue
-		}
-
-		if idx > 0 {
-			group := strings.Join(atoms[:idx], ".")
-			content.Group = loader.NamedGroup(group)
-		}
-
-		atoms = atoms[idx+1:]
-		break
+	var err error
+	content.Data, err = io.ReadAll(f)
+	if err != nil {
+		return loader.Content{}, err
 	}
 
-	if content.Role == loader.NoRole {
-		return loader.Content{}, nil
-	}
+	return content, nil
+}
 
-	for len(atoms) != 0 {
-		atom := atoms[0]
-		attr, ok := strings.CutPrefix(atom, "@")
-		if !ok {
-			break
-		}
-		atoms = atoms[1:]
+// LoadGoldenContent is a [ContentLoader] that implements the "<name>.golden"
+// convention used throughout the Go ecosystem.
+//
+// A file named "<name>.golden" is the expected output for the input in the
+// sibling file named "<name>". The language of both the input and the output
+// is determined by the file extension of "<name>". For example, "foo.json" is
+// the input for the expected output in "foo.json.golden", and both are JSON.
+//
+// Files without a ".golden" sibling are ignored.
+func LoadGoldenContent(name string, f fs.File) (loader.Content, error) {
+	base := path.Base(name)
 
-		if content.Attributes == nil {
-			content.Attributes = make(map[string]string)
-		}
+	content := loader.Content{
+		Role:     loader.Input,
+		Optional: true,
+	}
 
-		if pos := strings.Index(attr, "="); pos != -1 {
-			content.Attributes[attr[:pos]] = attr[pos+1:]
-		} else {
-			content.Attributes[attr] = ""
-		}
+	if stem, ok := strings.CutSuffix(base, ".golden"); ok && stem != "" {
+		base = stem
+		content.Role = loader.Output
+		content.Optional = false
 	}
 
-	content.Language = strings.Join(atoms, ".")
+	content.Group = loader.NamedGroup(base)
+	content.Language = strings.TrimPrefix(path.Ext(base), ".")
 
 	var err error
 	content.Data, err = io.ReadAll(f)
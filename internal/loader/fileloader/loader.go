@@ -1,7 +1,11 @@
 package fileloader
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io/fs"
+	"maps"
 	"path"
 	"strings"
 
@@ -24,6 +28,7 @@ func NewLoader(options ...LoadOption) *Loader {
 			FS:          rootfs.FS,
 			Recurse:     true,
 			LoadContent: LoadContent,
+			Layout:      FlatLayout,
 		},
 	}
 
@@ -44,32 +49,154 @@ func (l *Loader) Load(dir string, options ...LoadOption) (test.Test, error) {
 		opt(&opts)
 	}
 
+	switch opts.Layout {
+	case CaseLayout:
+		return loadCaseDir(opts, dir, true)
+	case VariantLayout:
+		return loadVariantDir(opts, dir)
+	}
+
 	return loader.LoadDir(
 		opts.FS,
 		dir,
 		opts.Recurse,
 		func(builder *loader.TestBuilder, fsys fs.FS, filePath string) error {
-			f, err := fsys.Open(filePath)
-			if err != nil {
-				return err
-			}
-			defer f.Close()
+			return loadFile(builder, opts, filePath, nil)
+		},
+	)
+}
 
-			name := path.Base(filePath)
-			name, skip := strings.CutPrefix(name, "_")
+// loadFile adds the content loaded from the file at filePath to builder.
+//
+// attrs is a set of attributes that is applied to the content, in addition to
+// any attributes produced by the content loader.
+func loadFile(
+	builder *loader.TestBuilder,
+	opts loadOptions,
+	filePath string,
+	attrs map[string]string,
+) error {
+	f, err := opts.FS.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
 
-			c, err := opts.LoadContent(name, f)
-			if err != nil {
-				return err
-			}
+	name := path.Base(filePath)
+	name, skip := strings.CutPrefix(name, "_")
+
+	c, err := opts.LoadContent(name, f)
+	if err != nil {
+		return err
+	}
 
-			return builder.AddContent(
-				loader.ContentEnvelope{
-					File:    filePath,
-					Skip:    skip,
-					Content: c,
-				},
-			)
+	if len(attrs) != 0 {
+		merged := maps.Clone(attrs)
+		maps.Copy(merged, c.Attributes)
+		c.Attributes = merged
+	}
+
+	return builder.AddContent(
+		loader.ContentEnvelope{
+			File:    filePath,
+			Skip:    skip,
+			Content: c,
 		},
 	)
 }
+
+const (
+	// caseAttributesFile is the name of the file within a case directory that
+	// contains attributes that apply to all of the inputs and outputs within
+	// that directory.
+	caseAttributesFile = "attributes.json"
+
+	// caseDescriptionFile is the name of the file within a case directory that
+	// contains a description of the test case.
+	caseDescriptionFile = "README.md"
+)
+
+// loadCaseDir loads tests from a directory that uses the [CaseLayout].
+//
+// If root is true, dirPath is the directory that contains the test cases, in
+// which case its sub-directories are loaded even if recursion is disabled.
+func loadCaseDir(opts loadOptions, dirPath string, root bool) (test.Test, error) {
+	entries, err := fs.ReadDir(opts.FS, dirPath)
+	if err != nil {
+		return test.Test{}, err
+	}
+
+	attrs, err := loadCaseAttributes(opts.FS, path.Join(dirPath, caseAttributesFile))
+	if err != nil {
+		return test.Test{}, err
+	}
+
+	desc, err := loadCaseDescription(opts.FS, path.Join(dirPath, caseDescriptionFile))
+	if err != nil {
+		return test.Test{}, err
+	}
+
+	var builder loader.TestBuilder
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		entryPath := path.Join(dirPath, entry.Name())
+
+		if entry.IsDir() {
+			if root || opts.Recurse {
+				t, err := loadCaseDir(opts, entryPath, false)
+				if err != nil {
+					return test.Test{}, err
+				}
+				builder.AddTest(t)
+			}
+		} else if entry.Name() != caseAttributesFile && entry.Name() != caseDescriptionFile {
+			if err := loadFile(&builder, opts, entryPath, attrs); err != nil {
+				return test.Test{}, err
+			}
+		}
+	}
+
+	name := path.Base(dirPath)
+	name, skip := strings.CutPrefix(name, "_")
+
+	return builder.BuildTest(
+		name,
+		test.WithSkip(skip),
+		test.WithDescription(desc),
+	)
+}
+
+// loadCaseAttributes loads the attributes in the JSON file at filePath, if it
+// exists.
+func loadCaseAttributes(fsys fs.FS, filePath string) (map[string]string, error) {
+	data, err := fs.ReadFile(fsys, filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var attrs map[string]string
+	if err := json.Unmarshal(data, &attrs); err != nil {
+		return nil, fmt.Errorf("%s: attributes must be a JSON object with string values: %w", filePath, err)
+	}
+
+	return attrs, nil
+}
+
+// loadCaseDescription loads the description in the file at filePath, if it
+// exists.
+func loadCaseDescription(fsys fs.FS, filePath string) (string, error) {
+	data, err := fs.ReadFile(fsys, filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
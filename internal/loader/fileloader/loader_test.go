@@ -39,3 +39,44 @@ func TestWithRecursion(t *testing.T) {
 		t.Fatal(string(d))
 	}
 }
+
+func TestLoadGoldenContent(t *testing.T) {
+	loadertest.RunDir(
+		t,
+		NewLoader().Load,
+		"testdata/golden",
+		"testdata/golden/.expect.golden",
+		WithFileLoader(LoadGoldenContent),
+	)
+}
+
+func TestWithLayout(t *testing.T) {
+	loadertest.RunDir(
+		t,
+		NewLoader().Load,
+		"testdata/case-layout",
+		"testdata/case-layout/.expect.case-layout",
+		WithLayout(CaseLayout),
+	)
+}
+
+func TestWithLayout_variant(t *testing.T) {
+	loadertest.RunDir(
+		t,
+		NewLoader().Load,
+		"testdata/variant-layout",
+		"testdata/variant-layout/.expect.variant-layout",
+		WithLayout(VariantLayout),
+	)
+}
+
+func TestWithLayout_noRecursion(t *testing.T) {
+	loadertest.RunDir(
+		t,
+		NewLoader().Load,
+		"testdata/case-layout",
+		"testdata/case-layout/.expect.case-layout.no-recursion",
+		WithLayout(CaseLayout),
+		WithRecursion(false),
+	)
+}
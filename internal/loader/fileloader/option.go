@@ -11,8 +11,58 @@ type loadOptions struct {
 	FS          fs.FS
 	Recurse     bool
 	LoadContent ContentLoader
+	Layout      Layout
 }
 
+// Layout is an enumeration of the ways in which test files may be arranged
+// within directories.
+type Layout int
+
+const (
+	// FlatLayout is a [Layout] in which the inputs and outputs of many tests
+	// may be placed within the same directory. Inputs and outputs are grouped
+	// into tests according to their file names, and each directory produces a
+	// test that contains the tests within it.
+	//
+	// This is the default layout.
+	FlatLayout Layout = iota
+
+	// CaseLayout is a [Layout] in which each directory is a single test case,
+	// named after the directory.
+	//
+	// Inputs and outputs without a group prefix, such as "input.json" and
+	// "output.json", belong to the test for the directory itself, rather than
+	// to an un-named sub-test. If the directory contains multiple such inputs
+	// or outputs they form a test matrix.
+	//
+	// An optional "attributes.json" file within the directory contains a JSON
+	// object of string attributes that apply to every input and output in that
+	// directory. An optional "README.md" file contains a description of the
+	// test case.
+	//
+	// The sub-directories of the directory being loaded are always loaded as
+	// test cases. Recursion only controls whether their own sub-directories
+	// are loaded as nested test cases.
+	CaseLayout
+
+	// VariantLayout is a [Layout] in which a single set of inputs is tested
+	// against several "variants" of expected outputs.
+	//
+	// Any directory that contains an "inputs" sub-directory is a set of variant
+	// tests. Each file in the "inputs" directory is paired with the files that
+	// have the same base name (excluding the file extension) in each of the
+	// "outputs/<variant>" directories. For example, "inputs/foo.md" is paired
+	// with "outputs/html/foo.html" and "outputs/text/foo.txt". The language of
+	// each input and output is determined by its file extension.
+	//
+	// Each variant produces a separate sub-test, and the name of the variant
+	// is available in the output's "variant" attribute.
+	//
+	// Directories that do not contain an "inputs" sub-directory are treated
+	// the same as the [FlatLayout].
+	VariantLayout
+)
+
 // WithRecursion if a [LoadOption] that enables or disables recursive scanning
 // of sub-directories.
 //
@@ -31,6 +81,16 @@ func WithFS(f fs.FS) LoadOption {
 	}
 }
 
+// WithLayout is a [LoadOption] that configures the [Layout] of test files
+// within directories.
+//
+// The [FlatLayout] is used by default.
+func WithLayout(l Layout) LoadOption {
+	return func(opts *loadOptions) {
+		opts.Layout = l
+	}
+}
+
 // WithFileLoader is a [LoadOption] that configures an alternative [FileLoader]
 // used to identify test files and load their content.
 func WithFileLoader(load ContentLoader) LoadOption {
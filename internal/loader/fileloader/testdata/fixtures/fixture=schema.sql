@@ -0,0 +1 @@
+CREATE TABLE t;
@@ -0,0 +1 @@
+INSERT INTO t;
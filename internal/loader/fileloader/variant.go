@@ -0,0 +1,211 @@
+package fileloader
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/dogmatiq/aureus/internal/loader"
+	"github.com/dogmatiq/aureus/internal/test"
+)
+
+const (
+	// variantInputsDir is the name of the directory that contains the inputs
+	// that are shared by all variants when using the [VariantLayout].
+	variantInputsDir = "inputs"
+
+	// variantOutputsDir is the name of the directory that contains a
+	// sub-directory of expected outputs for each variant when using the
+	// [VariantLayout].
+	variantOutputsDir = "outputs"
+
+	// variantAttr is the name of the attribute that contains the name of the
+	// variant when using the [VariantLayout].
+	variantAttr = "variant"
+)
+
+// loadVariantDir loads tests from a directory that uses the [VariantLayout].
+func loadVariantDir(opts loadOptions, dirPath string) (test.Test, error) {
+	entries, err := fs.ReadDir(opts.FS, dirPath)
+	if err != nil {
+		return test.Test{}, err
+	}
+
+	var builder loader.TestBuilder
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		entryPath := path.Join(dirPath, entry.Name())
+
+		switch {
+		case !entry.IsDir():
+			err = loadFile(&builder, opts, entryPath, nil)
+		case entry.Name() == variantInputsDir:
+			err = loadVariantFiles(&builder, opts, entryPath, loader.Input, "", false)
+		case entry.Name() == variantOutputsDir:
+			err = loadVariantOutputs(&builder, opts, entryPath)
+		case opts.Recurse:
+			var t test.Test
+			t, err = loadVariantDir(opts, entryPath)
+			builder.AddTest(t)
+		}
+
+		if err != nil {
+			return test.Test{}, err
+		}
+	}
+
+	name := path.Base(dirPath)
+	name, skip := strings.CutPrefix(name, "_")
+
+	subTests, err := builder.Build()
+	if err != nil {
+		return test.Test{}, err
+	}
+
+	for i, t := range subTests {
+		subTests[i] = nestByVariant(t, path.Join(dirPath, variantOutputsDir))
+	}
+
+	return test.New(
+		name,
+		test.WithSkip(skip),
+		test.WithSubTests(subTests...),
+	), nil
+}
+
+// nestByVariant moves each of the assertions of t that compare against a
+// variant's output into a sub-test named after that variant.
+//
+// Only outputs loaded from a variant directory within outputsDir are nested.
+// Other content may use the "variant" attribute for its own purposes.
+//
+// Inputs with several variants already have a sub-test for each variant, but
+// an input with a single variant does not. This ensures that the test names
+// are the same regardless of the number of variants.
+func nestByVariant(t test.Test, outputsDir string) test.Test {
+	var assertions []test.Assertion
+
+	for _, a := range t.Assertions {
+		variant, ok := a.Output.Attributes[variantAttr]
+		if !ok || path.Dir(path.Dir(a.Output.File)) != outputsDir {
+			assertions = append(assertions, a)
+			continue
+		}
+
+		t.SubTests = append(
+			t.SubTests,
+			test.New(
+				variant,
+				test.WithSkip(t.Skip),
+				test.WithAssertions(a),
+			),
+		)
+	}
+
+	if len(assertions) != len(t.Assertions) {
+		t.Skip = false
+		t.Assertions = assertions
+	}
+
+	return t
+}
+
+// loadVariantOutputs adds the expected outputs within each of the variant
+// directories within dirPath to builder.
+func loadVariantOutputs(
+	builder *loader.TestBuilder,
+	opts loadOptions,
+	dirPath string,
+) error {
+	entries, err := fs.ReadDir(opts.FS, dirPath)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		variant, skip := strings.CutPrefix(entry.Name(), "_")
+
+		if err := loadVariantFiles(
+			builder,
+			opts,
+			path.Join(dirPath, entry.Name()),
+			loader.Output,
+			variant,
+			skip,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadVariantFiles adds each of the files within dirPath to builder, grouped
+// by their base name.
+func loadVariantFiles(
+	builder *loader.TestBuilder,
+	opts loadOptions,
+	dirPath string,
+	role loader.ContentRole,
+	variant string,
+	skip bool,
+) error {
+	entries, err := fs.ReadDir(opts.FS, dirPath)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		filePath := path.Join(dirPath, entry.Name())
+
+		data, err := fs.ReadFile(opts.FS, filePath)
+		if err != nil {
+			return err
+		}
+
+		name, skipFile := strings.CutPrefix(entry.Name(), "_")
+		ext := path.Ext(name)
+		base := strings.TrimSuffix(name, ext)
+		if base == "" {
+			base = name
+		}
+
+		c := loader.Content{
+			Role:     role,
+			Group:    loader.NamedGroup(base),
+			Language: strings.TrimPrefix(ext, "."),
+			Data:     data,
+		}
+
+		if variant != "" {
+			c.Caption = variant
+			c.Attributes = map[string]string{
+				variantAttr: variant,
+			}
+		}
+
+		if err := builder.AddContent(
+			loader.ContentEnvelope{
+				File:    filePath,
+				Skip:    skip || skipFile,
+				Content: c,
+			},
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
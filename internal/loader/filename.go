@@ -0,0 +1,130 @@
+package loader
+
+import (
+	"path"
+	"strings"
+)
+
+// ParseFileName returns the (data-less) content described by a file name that
+// follows the "<group>.input[.<attributes>][.<language>]" and
+// "<group>.output[.<attributes>][.<language>]" naming conventions.
+//
+// The "input" and "output" atoms may be given as "input=<name>" and
+// "output=<name>" to name the content, such as "test.input=old.json" or
+// "test.output=stderr.txt". See [Content.Name].
+//
+// Files that follow the "<group>.fixture[=<name>][.<attributes>][.<language>]"
+// naming convention are fixtures. See [Fixture]. Likewise, files that use a
+// "roundtrip" atom in place of "output" contain the expected result of a
+// round-trip. See [RoundTrip].
+//
+// Each attribute is a dot-separated "atom" that begins with an "@", such as
+// "@key=value" or "@flag". The optional group prefix may itself contain dots.
+//
+// An attribute with a comma-separated list of values, such as "@width=80,120",
+// is a parameter. See [Content.Parameters].
+//
+// The "@goos", "@goarch" and "@go" attributes describe the platform to which
+// the content applies. See [Content.Platform].
+//
+// If name does not follow either convention, the returned content's role is
+// [NoRole].
+func ParseFileName(name string) Content {
+	base := path.Base(name)
+	atoms := strings.Split(base, ".")
+
+	content := Content{
+		// If there is no prefix on the filename before the .input or .output
+		// atom marker, we still want to group the inputs and outputs into a
+		// test matrix.
+		Group: UnnamedGroup(),
+	}
+
+	for idx, atom := range atoms {
+		if strings.EqualFold(atom, "input") {
+			content.Role = Input
+		} else if name, ok := strings.CutPrefix(atom, "input="); ok && name != "" {
+			content.Role = Input
+			content.Name = name
+		} else if strings.EqualFold(atom, "output") {
+			content.Role = Output
+		} else if name, ok := strings.CutPrefix(atom, "output="); ok && name != "" {
+			content.Role = Output
+			content.Name = name
+		} else if strings.EqualFold(atom, "roundtrip") {
+			content.Role = RoundTrip
+		} else if strings.EqualFold(atom, "fixture") {
+			content.Role = Fixture
+		} else if name, ok := strings.CutPrefix(atom, "fixture="); ok && name != "" {
+			content.Role = Fixture
+			content.Name = name
+		} else {
+			continue
+		}
+
+		if idx > 0 {
+			group := strings.Join(atoms[:idx], ".")
+			content.Group = NamedGroup(group)
+		}
+
+		atoms = atoms[idx+1:]
+		break
+	}
+
+	if content.Role == NoRole {
+		return Content{}
+	}
+
+	for len(atoms) != 0 {
+		atom := atoms[0]
+		attr, ok := strings.CutPrefix(atom, "@")
+		if !ok {
+			break
+		}
+		atoms = atoms[1:]
+
+		// Numeric atoms that follow an attribute with a value are parts of a
+		// version number, such as "@go=1.25".
+		if strings.Contains(attr, "=") {
+			for len(atoms) != 0 && isNumeric(atoms[0]) {
+				attr += "." + atoms[0]
+				atoms = atoms[1:]
+			}
+		}
+
+		if content.Attributes == nil {
+			content.Attributes = make(map[string]string)
+		}
+
+		if k, v, ok := strings.Cut(attr, "="); !ok {
+			content.Attributes[attr] = ""
+		} else if strings.Contains(v, ",") {
+			if content.Parameters == nil {
+				content.Parameters = map[string][]string{}
+			}
+			content.Parameters[k] = ParseParameterList(v)
+		} else {
+			content.Attributes[k] = v
+		}
+	}
+
+	content.Platform = ExtractPlatform(content.Attributes)
+	content.Language = strings.Join(atoms, ".")
+
+	return content
+}
+
+// isNumeric returns true if s is a non-empty string of decimal digits.
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
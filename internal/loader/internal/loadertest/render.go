@@ -23,8 +23,16 @@ func RenderTest(t test.Test) []byte {
 		w.WriteString(" [skipped]")
 	}
 
+	if t.Pipeline {
+		w.WriteString(" [pipeline]")
+	}
+
 	w.WriteString(" {\n")
 
+	if t.Description != "" {
+		fmt.Fprintf(&w, "    description = %q\n", t.Description)
+	}
+
 	for _, s := range t.SubTests {
 		indent(&w, RenderTest(s))
 	}
@@ -43,6 +51,15 @@ func renderAssertion(a test.Assertion) []byte {
 	w.WriteString("assertion {\n")
 	indent(&w, renderContent("input", a.Input))
 	indent(&w, renderContent("output", a.Output))
+	if a.RoundTrip != nil {
+		indent(&w, renderContent("roundtrip", *a.RoundTrip))
+	}
+	for _, n := range a.NamedInputs {
+		indent(&w, renderContent("named-input", n))
+	}
+	for _, f := range a.Fixtures {
+		indent(&w, renderContent("fixture", f))
+	}
 	w.WriteString("}")
 	return w.Bytes()
 }
@@ -61,6 +78,14 @@ func renderContent(label string, c test.Content) []byte {
 
 	w.WriteString(" {\n")
 
+	if c.Name != "" {
+		fmt.Fprintf(&w, "    name = %q\n", c.Name)
+	}
+
+	if c.Stage != "" {
+		fmt.Fprintf(&w, "    stage = %q\n", c.Stage)
+	}
+
 	if c.Language != "" {
 		fmt.Fprintf(&w, "    lang = %q\n", c.Language)
 	}
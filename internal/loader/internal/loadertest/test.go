@@ -33,29 +33,42 @@ func Run[O any](
 		dir := filepath.Join("testdata", e.Name())
 
 		t.Run(e.Name(), func(t *testing.T) {
-			var actual []byte
-			test, err := load(dir)
-			if err != nil {
-				actual = []byte(err.Error())
-			} else {
-				actual = RenderTest(test)
-			}
-
-			expectFile := filepath.Join(dir, ".expect")
-			expect, err := os.ReadFile(expectFile)
-			if err != nil {
-				t.Fatal(err)
-			}
-
-			expect = bytes.TrimSpace(expect)
-			actual = bytes.TrimSpace(actual)
-
-			if d := diff.Diff(
-				expectFile, expect,
-				"actual.json", actual,
-			); d != nil {
-				t.Fatal(string(d))
-			}
+			RunDir(t, load, dir, filepath.Join(dir, ".expect"))
 		})
 	}
 }
+
+// RunDir executes a single golden-file test for a loader, by loading the tests
+// in dir using the given options and comparing the result to the content of
+// expectFile.
+func RunDir[O any](
+	t *testing.T,
+	load func(string, ...O) (test.Test, error),
+	dir, expectFile string,
+	options ...O,
+) {
+	t.Helper()
+
+	var actual []byte
+	test, err := load(dir, options...)
+	if err != nil {
+		actual = []byte(err.Error())
+	} else {
+		actual = RenderTest(test)
+	}
+
+	expect, err := os.ReadFile(expectFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect = bytes.TrimSpace(expect)
+	actual = bytes.TrimSpace(actual)
+
+	if d := diff.Diff(
+		expectFile, expect,
+		"actual.json", actual,
+	); d != nil {
+		t.Fatal(string(d))
+	}
+}
@@ -0,0 +1,52 @@
+// Command platformgen generates the list of GOOS/GOARCH pairs that are
+// supported by the Go toolchain, as reported by "go tool dist list".
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"os/exec"
+)
+
+func main() {
+	out := flag.String("o", "platform_list.go", "the file to write")
+	flag.Parse()
+
+	data, err := exec.Command("go", "tool", "dist", "list", "-json").Output()
+	if err != nil {
+		log.Fatalf("unable to list platforms: %s", err)
+	}
+
+	var platforms []struct {
+		GOOS   string
+		GOARCH string
+	}
+	if err := json.Unmarshal(data, &platforms); err != nil {
+		log.Fatalf("unable to parse platform list: %s", err)
+	}
+
+	var w bytes.Buffer
+	w.WriteString("// Code generated by platformgen. DO NOT EDIT.\n\n")
+	w.WriteString("package loader\n\n")
+	w.WriteString("// knownPlatforms is the list of GOOS/GOARCH pairs supported by the Go\n")
+	w.WriteString("// toolchain, as reported by \"go tool dist list\".\n")
+	w.WriteString("var knownPlatforms = [][2]string{\n")
+	for _, p := range platforms {
+		fmt.Fprintf(&w, "\t{%q, %q},\n", p.GOOS, p.GOARCH)
+	}
+	w.WriteString("}\n")
+
+	src, err := format.Source(w.Bytes())
+	if err != nil {
+		log.Fatalf("unable to format generated code: %s", err)
+	}
+
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatalf("unable to write %s: %s", *out, err)
+	}
+}
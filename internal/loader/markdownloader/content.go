@@ -26,45 +26,95 @@ type ContentLoader func(
 ) (_ loader.Content, skip bool, _ error)
 
 // LoadContent is the default [ContentLoader] implementation.
+//
+// It identifies test inputs and outputs using attributes with the "au:" prefix,
+// such as "au:input" and "au:output".
 func LoadContent(
 	headings []string,
 	info, code string,
 ) (loader.Content, bool, error) {
-	lang, attrs, err := ParseInfoString(info)
-	if err != nil {
-		return loader.Content{}, false, err
+	return loadContent(attrPrefix, headings, info, code)
+}
+
+// LoadContentWithPrefix returns a [ContentLoader] that behaves like
+// [LoadContent], except that it uses attributes with the given prefix instead
+// of "au:".
+func LoadContentWithPrefix(prefix string) ContentLoader {
+	return func(headings []string, info, code string) (loader.Content, bool, error) {
+		return loadContent(prefix, headings, info, code)
 	}
+}
 
-	isInput, err := extractFlag(attrs, inputAttr)
+func loadContent(
+	prefix string,
+	headings []string,
+	info, code string,
+) (loader.Content, bool, error) {
+	lang, attrs, err := parseInfoString(prefix, info)
 	if err != nil {
 		return loader.Content{}, false, err
 	}
 
-	isOutput, err := extractFlag(attrs, outputAttr)
+	isInput, inputName := extractFlagOrValue(attrs, prefix, inputAttr)
+
+	isOutput, outputName := extractFlagOrValue(attrs, prefix, outputAttr)
+	isFixture, fixtureName := extractFlagOrValue(attrs, prefix, fixtureAttr)
+
+	isRoundTrip, err := extractFlag(attrs, prefix, roundTripAttr)
 	if err != nil {
 		return loader.Content{}, false, err
 	}
 
-	if isInput && isOutput {
+	if countTrue(isInput, isOutput, isFixture, isRoundTrip) > 1 {
 		return loader.Content{}, false, fmt.Errorf(
-			"only one of '%s%s' and '%s%s' may be specified",
-			attrPrefix, inputAttr,
-			attrPrefix, outputAttr,
+			"only one of '%s%s', '%s%s', '%s%s' and '%s%s' may be specified",
+			prefix, inputAttr,
+			prefix, outputAttr,
+			prefix, fixtureAttr,
+			prefix, roundTripAttr,
 		)
 	}
 
-	group, err := extractValue(attrs, groupAttr)
+	group, err := extractValue(attrs, prefix, groupAttr)
+	if err != nil {
+		return loader.Content{}, false, err
+	}
+
+	skip, err := extractFlag(attrs, prefix, skipAttr)
 	if err != nil {
 		return loader.Content{}, false, err
 	}
 
-	skip, err := extractFlag(attrs, skipAttr)
+	stage, err := extractValue(attrs, prefix, stageAttr)
 	if err != nil {
 		return loader.Content{}, false, err
 	}
 
+	matrix, err := extractValue(attrs, prefix, matrixAttr)
+	if err != nil {
+		return loader.Content{}, false, err
+	}
+
+	var platform loader.Platform
+
+	for k, v := range map[string]*string{
+		goosAttr:   &platform.GOOS,
+		goarchAttr: &platform.GOARCH,
+		goAttr:     &platform.Go,
+	} {
+		*v, err = extractValue(attrs, prefix, k)
+		if err != nil {
+			return loader.Content{}, false, err
+		}
+	}
+
+	params, err := parseMatrix(matrix)
+	if err != nil {
+		return loader.Content{}, false, fmt.Errorf("%q attribute: %w", prefix+matrixAttr, err)
+	}
+
 	for k := range attrs {
-		if strings.HasPrefix(k, attrPrefix) {
+		if strings.HasPrefix(k, prefix) {
 			return loader.Content{}, false, fmt.Errorf("unrecognized attribute %q", k)
 		}
 	}
@@ -72,6 +122,8 @@ func LoadContent(
 	c := loader.Content{
 		Language:   lang,
 		Attributes: attrs,
+		Parameters: params,
+		Platform:   platform,
 		Data:       []byte(code),
 	}
 
@@ -83,10 +135,25 @@ func LoadContent(
 		c.Caption = headings[len(headings)-1]
 	}
 
+	if stage != "" && !isOutput {
+		return loader.Content{}, false, fmt.Errorf(
+			"'%s%s' may only be specified on outputs",
+			prefix, stageAttr,
+		)
+	}
+
 	if isInput {
 		c.Role = loader.Input
+		c.Name = inputName
 	} else if isOutput {
 		c.Role = loader.Output
+		c.Name = outputName
+		c.Stage = stage
+	} else if isFixture {
+		c.Role = loader.Fixture
+		c.Name = fixtureName
+	} else if isRoundTrip {
+		c.Role = loader.RoundTrip
 	} else {
 		return loader.Content{}, false, nil
 	}
@@ -96,10 +163,17 @@ func LoadContent(
 
 // ParseInfoString parses the "info string" of a fenced code block into a
 // language and a map of attributes.
+//
+// The first attribute is treated as the language, unless it has a value or
+// begins with the "au:" prefix.
 func ParseInfoString(info string) (lang string, attrs map[string]string, err error) {
+	return parseInfoString(attrPrefix, info)
+}
+
+func parseInfoString(prefix, info string) (lang string, attrs map[string]string, err error) {
 	data := &bytes.Buffer{}
 	data.WriteString("<html ")
-	data.WriteString(info)
+	data.WriteString(escapeUnquotedValues(info))
 	data.WriteByte('>')
 
 	node, err := html.Parse(data)
@@ -109,7 +183,7 @@ func ParseInfoString(info string) (lang string, attrs map[string]string, err err
 
 	attrs = map[string]string{}
 	for i, attr := range node.FirstChild.Attr {
-		if i == 0 && attr.Val == "" && !strings.HasPrefix(attr.Key, attrPrefix) {
+		if i == 0 && attr.Val == "" && !strings.HasPrefix(attr.Key, prefix) {
 			lang = attr.Key
 		} else {
 			attrs[attr.Key] = attr.Val
@@ -119,16 +193,119 @@ func ParseInfoString(info string) (lang string, attrs map[string]string, err err
 	return lang, attrs, nil
 }
 
+// escapeUnquotedValues returns info with each ">" character that appears within
+// an unquoted attribute value replaced by a character reference. This allows
+// version constraints such as au:go=>=1.25 to be written without quotes, which
+// would otherwise end the HTML tag used to parse the info string.
+func escapeUnquotedValues(info string) string {
+	const (
+		name = iota
+		equals
+		unquoted
+		quoted
+	)
+
+	var (
+		w     strings.Builder
+		state = name
+		quote byte
+	)
+
+	for i := 0; i < len(info); i++ {
+		c := info[i]
+
+		switch state {
+		case name:
+			if c == '=' {
+				state = equals
+			}
+		case equals:
+			if c == '"' || c == '\'' {
+				state, quote = quoted, c
+			} else if !isSpace(c) {
+				state = unquoted
+			}
+		case quoted:
+			if c == quote {
+				state = name
+			}
+		}
+
+		if state == unquoted {
+			if isSpace(c) {
+				state = name
+			} else if c == '>' {
+				w.WriteString("&gt;")
+				continue
+			}
+		}
+
+		w.WriteByte(c)
+	}
+
+	return w.String()
+}
+
+// isSpace returns true if c is an HTML whitespace character.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
+
 const (
-	attrPrefix = "au:"
-	inputAttr  = "input"
-	outputAttr = "output"
-	groupAttr  = "group"
-	skipAttr   = "skip"
+	attrPrefix    = "au:"
+	inputAttr     = "input"
+	outputAttr    = "output"
+	groupAttr     = "group"
+	skipAttr      = "skip"
+	tableAttr     = "table"
+	matrixAttr    = "matrix"
+	sessionAttr   = "session"
+	fixtureAttr   = "fixture"
+	stageAttr     = "stage"
+	roundTripAttr = "roundtrip"
+	goosAttr      = "goos"
+	goarchAttr    = "goarch"
+	goAttr        = "go"
 )
 
-func extractFlag(attrs map[string]string, k string) (bool, error) {
-	k = attrPrefix + k
+// parseMatrix parses the value of the "matrix" attribute, which is a
+// space-separated list of parameters, such as "width=80,120 mode=a,b".
+func parseMatrix(matrix string) (map[string][]string, error) {
+	if matrix == "" {
+		return nil, nil
+	}
+
+	params := map[string][]string{}
+
+	for _, p := range strings.Fields(matrix) {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("%q is not a key=value parameter", p)
+		}
+
+		if _, ok := params[k]; ok {
+			return nil, fmt.Errorf("%q parameter is specified more than once", k)
+		}
+
+		params[k] = loader.ParseParameterList(v)
+	}
+
+	return params, nil
+}
+
+// countTrue returns the number of values that are true.
+func countTrue(values ...bool) int {
+	n := 0
+	for _, v := range values {
+		if v {
+			n++
+		}
+	}
+	return n
+}
+
+func extractFlag(attrs map[string]string, prefix, k string) (bool, error) {
+	k = prefix + k
 	v, ok := attrs[k]
 	if v != "" {
 		return false, fmt.Errorf("%q attribute must not have a value", k)
@@ -137,8 +314,15 @@ func extractFlag(attrs map[string]string, k string) (bool, error) {
 	return ok, nil
 }
 
-func extractValue(attrs map[string]string, k string) (string, error) {
-	k = attrPrefix + k
+func extractFlagOrValue(attrs map[string]string, prefix, k string) (bool, string) {
+	k = prefix + k
+	v, ok := attrs[k]
+	delete(attrs, k)
+	return ok, v
+}
+
+func extractValue(attrs map[string]string, prefix, k string) (string, error) {
+	k = prefix + k
 	v, ok := attrs[k]
 	if !ok {
 		return "", nil
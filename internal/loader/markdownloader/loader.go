@@ -1,8 +1,10 @@
 package markdownloader
 
 import (
+	"fmt"
 	"io/fs"
 	"path"
+	"slices"
 	"strings"
 
 	"github.com/dogmatiq/aureus/internal/loader"
@@ -10,6 +12,9 @@ import (
 	"github.com/dogmatiq/aureus/internal/test"
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/text"
 )
 
@@ -24,10 +29,10 @@ type Loader struct {
 func NewLoader(options ...LoadOption) *Loader {
 	l := &Loader{
 		options: loadOptions{
-			FS:          rootfs.FS,
-			Recurse:     true,
-			LoadContent: LoadContent,
-			Parser:      goldmark.DefaultParser(),
+			FS:      rootfs.FS,
+			Recurse: true,
+			Prefix:  attrPrefix,
+			Parser:  NewParser(),
 		},
 	}
 
@@ -48,6 +53,10 @@ func (l *Loader) Load(dir string, options ...LoadOption) (test.Test, error) {
 		opt(&opts)
 	}
 
+	if opts.LoadContent == nil {
+		opts.LoadContent = LoadContentWithPrefix(opts.Prefix)
+	}
+
 	return loader.LoadDir(
 		opts.FS,
 		dir,
@@ -58,6 +67,18 @@ func (l *Loader) Load(dir string, options ...LoadOption) (test.Test, error) {
 	)
 }
 
+// NewParser returns a new Markdown parser with the given extensions enabled,
+// in addition to the GitHub Flavored Markdown table extension that is required
+// to load table-driven tests.
+//
+// The parser returned by NewParser() is used by default.
+func NewParser(extensions ...goldmark.Extender) parser.Parser {
+	return goldmark.New(
+		goldmark.WithExtensions(extension.Table),
+		goldmark.WithExtensions(extensions...),
+	).Parser()
+}
+
 func loadFile(builder *loader.TestBuilder, opts loadOptions, filePath string) error {
 	name := path.Base(filePath)
 	name, skip := strings.CutPrefix(name, "_")
@@ -108,9 +129,31 @@ func loadDocument(
 		b        loader.TestBuilder
 		title    string
 		headings []string
+		marker   *tableMarker
 	)
 
 	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		if marker != nil {
+			t, ok := n.(*extast.Table)
+			if !ok {
+				return "", nil, errMarkerWithoutTable(filePath, opts.Prefix, *marker)
+			}
+
+			if err := loadTable(
+				&b,
+				filePath,
+				source,
+				headings,
+				*marker,
+				t,
+			); err != nil {
+				return "", nil, err
+			}
+
+			marker = nil
+			continue
+		}
+
 		switch n := n.(type) {
 		case *ast.Heading:
 			if n.Level == 1 {
@@ -131,6 +174,15 @@ func loadDocument(
 			headings[n.Level-1] = linesOf(n, source)
 			headings = headings[:n.Level]
 
+		case *ast.HTMLBlock:
+			m, ok, err := parseTableMarker(opts.Prefix, n, source)
+			if err != nil {
+				return "", nil, fmt.Errorf("%s:%d: %w", filePath, m.Line, err)
+			}
+			if ok {
+				marker = &m
+			}
+
 		case *ast.FencedCodeBlock:
 			if err := loadBlock(
 				&b,
@@ -145,6 +197,10 @@ func loadDocument(
 		}
 	}
 
+	if marker != nil {
+		return "", nil, errMarkerWithoutTable(filePath, opts.Prefix, *marker)
+	}
+
 	tests, err := b.Build()
 	return title, tests, err
 }
@@ -162,6 +218,10 @@ func loadBlock(
 		info = string(block.Info.Value(source))
 	}
 
+	if isSession(opts.Prefix, info) {
+		return loadSession(builder, opts.Prefix, filePath, source, headings, info, block)
+	}
+
 	code := linesOf(block, source)
 
 	content, skip, err := opts.LoadContent(headings, info, code)
@@ -169,6 +229,10 @@ func loadBlock(
 		return err
 	}
 
+	if content.Headings == nil {
+		content.Headings = slices.Clone(headings)
+	}
+
 	line, begin, end := locationOf(block, source)
 
 	return builder.AddContent(
@@ -11,3 +11,13 @@ func TestLoader(t *testing.T) {
 	loader := NewLoader()
 	loadertest.Run(t, loader.Load)
 }
+
+func TestWithAttributePrefix(t *testing.T) {
+	loadertest.RunDir(
+		t,
+		NewLoader().Load,
+		"testdata/custom-prefix",
+		"testdata/custom-prefix/.expect.custom-prefix",
+		WithAttributePrefix("test:"),
+	)
+}
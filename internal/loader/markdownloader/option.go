@@ -12,6 +12,7 @@ type LoadOption func(*loadOptions)
 type loadOptions struct {
 	FS          fs.FS
 	Recurse     bool
+	Prefix      string
 	LoadContent ContentLoader
 	Parser      parser.Parser
 }
@@ -42,6 +43,18 @@ func WithContentLoader(load ContentLoader) LoadOption {
 	}
 }
 
+// WithAttributePrefix is a [LoadOption] that configures the prefix of the
+// attributes that identify tests within Markdown documents, such as the
+// "au:" prefix of "au:input", "au:table" and "au:session".
+//
+// The prefix is also used by the default [ContentLoader], but not by any
+// loader configured using [WithContentLoader].
+func WithAttributePrefix(prefix string) LoadOption {
+	return func(opts *loadOptions) {
+		opts.Prefix = prefix
+	}
+}
+
 // WithParser is a [LoadOption] that configures an alternative Markdown parser
 // to use when loading tests.
 func WithParser(p parser.Parser) LoadOption {
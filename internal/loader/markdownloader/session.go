@@ -0,0 +1,219 @@
+package markdownloader
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/dogmatiq/aureus/internal/loader"
+	"github.com/dogmatiq/aureus/internal/test"
+	"github.com/yuin/goldmark/ast"
+)
+
+// sessionPrompt is the prefix of lines within a session block that contain a
+// command.
+const sessionPrompt = "$ "
+
+// isSession returns true if info is the info string of a session block, e.g.
+// "console au:session", where prefix is the attribute prefix, such as "au:".
+func isSession(prefix, info string) bool {
+	_, attrs, err := parseInfoString(prefix, info)
+	if err != nil {
+		return false
+	}
+
+	_, ok := attrs[prefix+sessionAttr]
+	return ok
+}
+
+// sessionStep is a single command within a session block, and its expected
+// output.
+type sessionStep struct {
+	Command    string
+	Line       int
+	Begin, End int
+}
+
+// loadSession adds a test to builder for a fenced code block that contains a
+// transcript of a shell session.
+//
+// Each line that begins with "$ " is a command, and the lines that follow it
+// are the command's expected output. Each command is a separate sub-test, named
+// by [sessionStepName]. The input of every sub-test is the complete list of
+// commands, such that the session can be run once and its output shared
+// between the sub-tests. The expected output of each sub-test is named after
+// the 1-based position of its command within the session.
+func loadSession(
+	builder *loader.TestBuilder,
+	prefix string,
+	filePath string,
+	source []byte,
+	headings []string,
+	info string,
+	block *ast.FencedCodeBlock,
+) error {
+	line, begin, end := locationOf(block, source)
+
+	lang, attrs, err := parseInfoString(prefix, info)
+	if err != nil {
+		return fmt.Errorf("%s:%d: %w", filePath, line, err)
+	}
+
+	if _, err := extractFlag(attrs, prefix, sessionAttr); err != nil {
+		return fmt.Errorf("%s:%d: %w", filePath, line, err)
+	}
+
+	group, err := extractValue(attrs, prefix, groupAttr)
+	if err != nil {
+		return fmt.Errorf("%s:%d: %w", filePath, line, err)
+	}
+
+	skip, err := extractFlag(attrs, prefix, skipAttr)
+	if err != nil {
+		return fmt.Errorf("%s:%d: %w", filePath, line, err)
+	}
+
+	for k := range attrs {
+		if strings.HasPrefix(k, prefix) {
+			return fmt.Errorf("%s:%d: unrecognized attribute %q", filePath, line, k)
+		}
+	}
+
+	var steps []sessionStep
+
+	lines := block.Lines()
+	for i := range lines.Len() {
+		seg := lines.At(i)
+		text := string(seg.Value(source))
+
+		if cmd, ok := strings.CutPrefix(text, sessionPrompt); ok {
+			steps = append(
+				steps,
+				sessionStep{
+					Command: strings.TrimRight(cmd, "\r\n"),
+					Line:    bytes.Count(source[:seg.Start], newline) + 1,
+					Begin:   seg.Stop,
+					End:     seg.Stop,
+				},
+			)
+		} else if len(steps) == 0 {
+			return fmt.Errorf(
+				"%s:%d: session must begin with a command prefixed by %q",
+				filePath,
+				line+1,
+				sessionPrompt,
+			)
+		} else {
+			steps[len(steps)-1].End = seg.Stop
+		}
+	}
+
+	caption := ""
+	if len(headings) > 0 {
+		caption = headings[len(headings)-1]
+	}
+
+	var commands strings.Builder
+	for _, s := range steps {
+		commands.WriteString(s.Command)
+		commands.WriteByte('\n')
+	}
+
+	t := test.New(
+		group,
+		test.WithSkip(skip),
+	)
+
+	if t.Name == "" {
+		t.Name = fmt.Sprintf("session on line %d", line)
+	}
+
+	for i, s := range steps {
+		in := loader.ContentEnvelope{
+			File:  filePath,
+			Line:  line,
+			Begin: int64(begin),
+			End:   int64(end),
+			Content: loader.Content{
+				Role:       loader.Input,
+				Caption:    caption,
+				Headings:   slices.Clone(headings),
+				Language:   lang,
+				Attributes: maps.Clone(attrs),
+				Data:       []byte(commands.String()),
+			},
+		}
+
+		out := loader.ContentEnvelope{
+			File:   filePath,
+			Line:   s.Line + 1,
+			Begin:  int64(s.Begin),
+			End:    int64(s.End),
+			Encode: encodeSessionOutput,
+			Content: loader.Content{
+				Role:     loader.Output,
+				Name:     strconv.Itoa(i + 1),
+				Caption:  caption,
+				Headings: slices.Clone(headings),
+				Data:     source[s.Begin:s.End],
+			},
+		}
+
+		t.SubTests = append(
+			t.SubTests,
+			test.New(
+				sessionStepName(i, s.Command),
+				test.WithSkip(skip),
+				test.WithAssertions(
+					test.Assertion{
+						Input:  in.AsTestContent(),
+						Output: out.AsTestContent(),
+					},
+				),
+			),
+		)
+	}
+
+	builder.AddTest(t)
+
+	return nil
+}
+
+// sessionStepName returns the name of the sub-test for the i'th (0-based)
+// command within a session.
+//
+// The name begins with the 1-based position of the command, so that it is
+// unique within the session. It is followed by the command itself, with any
+// characters other than letters, digits, dots, hyphens and underscores
+// replaced by spaces, as characters such as slashes have special meaning
+// within Go test names.
+func sessionStepName(i int, command string) string {
+	clean := strings.Map(
+		func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(".-_", r) {
+				return r
+			}
+			return ' '
+		},
+		command,
+	)
+
+	words := strings.Fields(clean)
+	return strings.Join(
+		append([]string{strconv.Itoa(i + 1)}, words...),
+		" ",
+	)
+}
+
+// encodeSessionOutput ensures that the output of a session command ends with a
+// newline, so that it does not run into the next command.
+func encodeSessionOutput(data []byte) []byte {
+	if len(data) != 0 && !bytes.HasSuffix(data, newline) {
+		data = append(data, '\n')
+	}
+	return data
+}
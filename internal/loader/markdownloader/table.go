@@ -0,0 +1,382 @@
+package markdownloader
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dogmatiq/aureus/internal/loader"
+	"github.com/dogmatiq/aureus/internal/test"
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// tableMarker is an HTML comment that marks the table that follows it as a
+// table of test cases, e.g. <!-- au:table json -->.
+type tableMarker struct {
+	Line       int
+	Group      string
+	Skip       bool
+	Language   string
+	Attributes map[string]string
+}
+
+// parseTableMarker parses n as a [tableMarker], if it is one. prefix is the
+// prefix of the marker and its attributes, such as "au:".
+func parseTableMarker(prefix string, n *ast.HTMLBlock, source []byte) (tableMarker, bool, error) {
+	if n.HTMLBlockType != ast.HTMLBlockType2 {
+		return tableMarker{}, false, nil
+	}
+
+	text := linesOf(n, source)
+	if n.HasClosure() {
+		text += string(n.ClosureLine.Value(source))
+	}
+
+	text = strings.TrimSpace(text)
+	text = strings.TrimPrefix(text, "<!--")
+	text = strings.TrimSuffix(text, "-->")
+
+	info, ok := strings.CutPrefix(strings.TrimSpace(text), prefix+tableAttr)
+	if !ok || (info != "" && info[0] != ' ' && info[0] != '\t') {
+		return tableMarker{}, false, nil
+	}
+
+	line, _, _ := locationOf(n, source)
+	m := tableMarker{Line: line + 1}
+
+	lang, attrs, err := parseInfoString(prefix, strings.TrimSpace(info))
+	if err != nil {
+		return m, false, err
+	}
+
+	m.Group, err = extractValue(attrs, prefix, groupAttr)
+	if err != nil {
+		return m, false, err
+	}
+
+	m.Skip, err = extractFlag(attrs, prefix, skipAttr)
+	if err != nil {
+		return m, false, err
+	}
+
+	for k := range attrs {
+		if strings.HasPrefix(k, prefix) {
+			return m, false, fmt.Errorf("unrecognized attribute %q", k)
+		}
+	}
+
+	m.Language = lang
+	m.Attributes = attrs
+
+	return m, true, nil
+}
+
+// errMarkerWithoutTable returns an error indicating that m is not followed by
+// a table.
+func errMarkerWithoutTable(filePath, prefix string, m tableMarker) error {
+	return fmt.Errorf(
+		"%s:%d: %s%s marker must be immediately followed by a table",
+		filePath,
+		m.Line,
+		prefix,
+		tableAttr,
+	)
+}
+
+const (
+	inputColumn  = "input"
+	outputColumn = "output"
+)
+
+// loadTable adds a test to builder for each row of a table that is marked with
+// a [tableMarker].
+//
+// The "input" and "output" columns contain the test's input and expected
+// output, respectively. Every other column is an attribute, keyed by the text
+// of the column's header.
+func loadTable(
+	builder *loader.TestBuilder,
+	filePath string,
+	source []byte,
+	headings []string,
+	m tableMarker,
+	table *extast.Table,
+) error {
+	header, ok := table.FirstChild().(*extast.TableHeader)
+	if !ok {
+		return fmt.Errorf("%s:%d: table has no header", filePath, m.Line)
+	}
+
+	var (
+		columns       []string
+		input, output = -1, -1
+	)
+
+	for c := header.FirstChild(); c != nil; c = c.NextSibling() {
+		name := cellText(c, source)
+
+		switch {
+		case strings.EqualFold(name, inputColumn) && input == -1:
+			input = len(columns)
+		case strings.EqualFold(name, outputColumn) && output == -1:
+			output = len(columns)
+		}
+
+		columns = append(columns, name)
+	}
+
+	if input == -1 || output == -1 {
+		return fmt.Errorf(
+			"%s:%d: table must have both an %q and an %q column",
+			filePath,
+			m.Line,
+			inputColumn,
+			outputColumn,
+		)
+	}
+
+	var rows loader.TestBuilder
+
+	for r := header.NextSibling(); r != nil; r = r.NextSibling() {
+		var (
+			cells []ast.Node
+			attrs = maps.Clone(m.Attributes)
+		)
+
+		for c := r.FirstChild(); c != nil; c = c.NextSibling() {
+			i := len(cells)
+			cells = append(cells, c)
+
+			if i != input && i != output && i < len(columns) {
+				attrs[columns[i]] = cellText(c, source)
+			}
+		}
+
+		in, err := cellContent(filePath, source, cells[input], loader.Input)
+		if err != nil {
+			return err
+		}
+
+		out, err := cellContent(filePath, source, cells[output], loader.Output)
+		if err != nil {
+			return err
+		}
+
+		group := loader.NamedGroup(fmt.Sprintf("line %d", in.Line))
+		for _, env := range []*loader.ContentEnvelope{&in, &out} {
+			env.Skip = m.Skip
+			env.Content.Group = group
+			env.Content.Language = m.Language
+			env.Content.Attributes = maps.Clone(attrs)
+			env.Content.Headings = slices.Clone(headings)
+
+			if len(headings) > 0 {
+				env.Content.Caption = headings[len(headings)-1]
+			}
+
+			if err := rows.AddContent(*env); err != nil {
+				return err
+			}
+		}
+	}
+
+	tests, err := rows.Build()
+	if err != nil {
+		return err
+	}
+
+	name := m.Group
+	if name == "" {
+		name = fmt.Sprintf("table on line %d", m.Line)
+	}
+
+	builder.AddTest(
+		test.New(
+			name,
+			test.WithSkip(m.Skip),
+			test.WithSubTests(tests...),
+		),
+	)
+
+	return nil
+}
+
+// cellContent returns the content of a table cell.
+func cellContent(
+	filePath string,
+	source []byte,
+	cell ast.Node,
+	role loader.ContentRole,
+) (loader.ContentEnvelope, error) {
+	var (
+		begin, end int
+		encode     = encodeCell
+	)
+
+	if seg, ok := cellSegment(cell); ok {
+		begin, end = seg.Start, seg.Stop
+	} else if b, e, ok := emptyCellSpan(cell, source); ok {
+		begin, end = b, e
+		encode = encodeEmptyCell
+	} else {
+		return loader.ContentEnvelope{}, fmt.Errorf(
+			"%s:%d: table row has too few cells",
+			filePath,
+			bytes.Count(source[:cell.Parent().Pos()], newline)+1,
+		)
+	}
+
+	return loader.ContentEnvelope{
+		File:   filePath,
+		Line:   bytes.Count(source[:begin], newline) + 1,
+		Begin:  int64(begin),
+		End:    int64(end),
+		Encode: encode,
+		Content: loader.Content{
+			Role: role,
+			Data: []byte(cellText(cell, source)),
+		},
+	}, nil
+}
+
+// cellSegment returns the segment of the source that contains the content of
+// a table cell. It returns false if the cell has no content.
+func cellSegment(cell ast.Node) (text.Segment, bool) {
+	lines := cell.Lines()
+	if lines.Len() == 0 {
+		return text.Segment{}, false
+	}
+
+	seg := lines.At(0)
+	return seg, seg.Start != seg.Stop
+}
+
+// emptyCellSpan returns the span of the source between the pipes that delimit
+// a cell that has no content, such that the content can be blessed.
+//
+// The parser represents a cell with no characters between its pipes, such as
+// the last cell in "| a ||", in the same way as the cells it adds to rows that
+// have fewer cells than the header, so the source is inspected to determine
+// whether the cell is actually present. It returns false if it is not.
+func emptyCellSpan(cell ast.Node, source []byte) (begin, end int, ok bool) {
+	if lines := cell.Lines(); lines.Len() != 0 {
+		seg := lines.At(0)
+
+		begin = seg.Start
+		for begin > 0 && isBlank(source[begin-1]) {
+			begin--
+		}
+
+		return begin, skipBlanks(source, seg.Stop), true
+	}
+
+	prev := cell.PreviousSibling()
+	if prev == nil {
+		return 0, 0, false
+	}
+
+	// Find the pipe that ends the previous cell.
+	var pipe int
+	if seg, ok := cellSegment(prev); ok {
+		pipe = skipBlanks(source, seg.Stop)
+	} else if _, pipe, ok = emptyCellSpan(prev, source); !ok {
+		return 0, 0, false
+	}
+
+	if pipe >= len(source) || source[pipe] != '|' {
+		return 0, 0, false
+	}
+
+	// Then find the pipe that ends this cell.
+	begin = pipe + 1
+	end = skipBlanks(source, begin)
+
+	if end >= len(source) || source[end] != '|' {
+		return 0, 0, false
+	}
+
+	return begin, end, true
+}
+
+// skipBlanks returns the offset of the first character in source at or after
+// offset that is not a space or tab.
+func skipBlanks(source []byte, offset int) int {
+	for offset < len(source) && isBlank(source[offset]) {
+		offset++
+	}
+	return offset
+}
+
+// isBlank returns true if c is a space or tab.
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// escapedCellText matches the escape sequences within the text of a table cell.
+//
+// Pipes are escaped as "\|" and newlines as "<br>". A literal "<br>" is
+// escaped as "&lt;br>", and any text that would otherwise be unescaped to a
+// literal "<br>" has an additional "amp;" inserted after its ampersand, such
+// that "&lt;br>" is escaped as "&amp;lt;br>", and so on.
+var escapedCellText = regexp.MustCompile(`\\\||<br>|&((?:amp;)*)lt;br>`)
+
+// unescapedCellText matches the text that must be escaped within a table cell. It
+// is the inverse of [escapedCellText].
+var unescapedCellText = regexp.MustCompile(`\||\n|<br>|&((?:amp;)*)lt;br>`)
+
+// cellText returns the unescaped text within a table cell.
+func cellText(cell ast.Node, source []byte) string {
+	return unescapeCell(linesOf(cell, source))
+}
+
+// unescapeCell returns the unescaped form of the text within a table cell.
+func unescapeCell(text string) string {
+	return escapedCellText.ReplaceAllStringFunc(
+		text,
+		func(m string) string {
+			switch m {
+			case `\|`:
+				return "|"
+			case "<br>":
+				return "\n"
+			case "&lt;br>":
+				return "<br>"
+			default:
+				return strings.Replace(m, "amp;", "", 1)
+			}
+		},
+	)
+}
+
+// encodeEmptyCell escapes data so that it can replace the content of a table
+// cell that was empty, including the space that separates it from the pipes.
+func encodeEmptyCell(data []byte) []byte {
+	data = encodeCell(data)
+	return append(append([]byte(" "), data...), ' ')
+}
+
+// encodeCell escapes data so that it can be placed within a table cell. It is
+// the inverse of [unescapeCell].
+func encodeCell(data []byte) []byte {
+	data = bytes.TrimRight(data, "\n")
+	return unescapedCellText.ReplaceAllFunc(
+		data,
+		func(m []byte) []byte {
+			switch string(m) {
+			case "|":
+				return []byte(`\|`)
+			case "\n":
+				return []byte("<br>")
+			case "<br>":
+				return []byte("&lt;br>")
+			default:
+				return append([]byte("&amp;"), m[1:]...)
+			}
+		},
+	)
+}
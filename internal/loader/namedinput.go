@@ -0,0 +1,95 @@
+package loader
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/dogmatiq/aureus/internal/test"
+)
+
+// hasUnseparatedNamedInputs returns true if the group has named inputs that
+// have not yet been separated from its other inputs.
+func (g *group) hasUnseparatedNamedInputs() bool {
+	if g.NamedInputs != nil {
+		return false
+	}
+
+	return slices.ContainsFunc(
+		g.Inputs,
+		func(env ContentEnvelope) bool {
+			return env.Content.Name != ""
+		},
+	)
+}
+
+// buildNamedInputTest builds a test for a group that contains named inputs.
+//
+// Rather than forming a matrix, the named inputs are attached to each
+// assertion built from the group's un-named inputs. If there are no un-named
+// inputs, the first named input is used as the assertion's primary input.
+func buildNamedInputTest(g *group) (test.Test, error) {
+	var named, unnamed []ContentEnvelope
+
+	for _, env := range g.Inputs {
+		if env.Content.Name == "" {
+			unnamed = append(unnamed, env)
+			continue
+		}
+
+		for _, x := range named {
+			if x.Content.Name == env.Content.Name {
+				return test.Test{}, fmt.Errorf(
+					"input loaded from %s has the same name (%q) as the input loaded from %s",
+					location(env, true),
+					env.Content.Name,
+					location(x, true),
+				)
+			}
+		}
+
+		named = append(named, env)
+	}
+
+	primary := unnamed
+	if len(primary) == 0 {
+		primary = named[:1]
+	}
+
+	t, err := buildTest(
+		&group{
+			Name:        g.Name,
+			Inputs:      primary,
+			Outputs:     g.Outputs,
+			NamedInputs: named,
+		},
+	)
+	if err != nil {
+		return test.Test{}, err
+	}
+
+	for _, env := range named {
+		t.Skip = t.Skip || env.Skip
+	}
+
+	return withNamedInputs(t, named), nil
+}
+
+// withNamedInputs returns a copy of t with the given named inputs attached to
+// each of its assertions, including those within sub-tests.
+func withNamedInputs(t test.Test, named []ContentEnvelope) test.Test {
+	t.SubTests = slices.Clone(t.SubTests)
+	for i, s := range t.SubTests {
+		t.SubTests[i] = withNamedInputs(s, named)
+	}
+
+	t.Assertions = slices.Clone(t.Assertions)
+	for i, a := range t.Assertions {
+		a.NamedInputs = nil
+		for _, env := range named {
+			a.NamedInputs = append(a.NamedInputs, env.AsTestContent())
+		}
+		t.Assertions[i] = a
+	}
+
+	return t
+}
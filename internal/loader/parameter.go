@@ -0,0 +1,188 @@
+package loader
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/dogmatiq/aureus/internal/test"
+)
+
+// ParseParameterList parses a comma-separated list of parameter values.
+func ParseParameterList(v string) []string {
+	return strings.Split(v, ",")
+}
+
+// combination is a single combination of parameter values.
+type combination map[string]string
+
+// combinationsOf returns the cartesian product of the given parameters.
+//
+// It returns a single empty combination if there are no parameters.
+func combinationsOf(params map[string][]string) []combination {
+	combos := []combination{{}}
+
+	for _, k := range slices.Sorted(maps.Keys(params)) {
+		var next []combination
+
+		for _, c := range combos {
+			for _, v := range params[k] {
+				x := maps.Clone(c)
+				x[k] = v
+				next = append(next, x)
+			}
+		}
+
+		combos = next
+	}
+
+	return combos
+}
+
+// Name returns a human-readable name for the combination, such as
+// "mode=a width=80".
+func (c combination) Name() string {
+	var w strings.Builder
+
+	for _, k := range slices.Sorted(maps.Keys(c)) {
+		if w.Len() != 0 {
+			w.WriteByte(' ')
+		}
+		w.WriteString(k)
+		w.WriteByte('=')
+		w.WriteString(c[k])
+	}
+
+	return w.String()
+}
+
+// Matches returns true if env is compatible with the combination.
+//
+// Content is compatible if, for each parameter in the combination, it either
+// has a parameter or attribute with the same value, or does not mention the
+// parameter at all.
+func (c combination) Matches(env ContentEnvelope) bool {
+	for k, v := range c {
+		if values, ok := env.Content.Parameters[k]; ok {
+			if !slices.Contains(values, v) {
+				return false
+			}
+		} else if x, ok := env.Content.Attributes[k]; ok && x != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Apply returns a copy of env in which each of its parameters is replaced by
+// an attribute containing the parameter's value within the combination.
+func (c combination) Apply(env ContentEnvelope) ContentEnvelope {
+	if len(env.Content.Parameters) == 0 {
+		return env
+	}
+
+	attrs := maps.Clone(env.Content.Attributes)
+	if attrs == nil {
+		attrs = map[string]string{}
+	}
+
+	for k := range env.Content.Parameters {
+		if v, ok := c[k]; ok {
+			attrs[k] = v
+		}
+	}
+
+	env.Content.Attributes = attrs
+	env.Content.Parameters = nil
+
+	return env
+}
+
+// hasParameters returns true if any of the group's content has parameters.
+func (g *group) hasParameters() bool {
+	for _, env := range g.Inputs {
+		if len(env.Content.Parameters) != 0 {
+			return true
+		}
+	}
+
+	for _, env := range g.Outputs {
+		if len(env.Content.Parameters) != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// buildParameterizedTest builds a test for a group that contains content with
+// parameters.
+//
+// Each input is expanded into the cartesian product of its parameter values,
+// and each combination of values is paired with the outputs that are
+// compatible with it, forming a sub-test for each combination.
+func buildParameterizedTest(g *group) (test.Test, error) {
+	var (
+		groups []*group
+		index  = map[string]*group{}
+		used   = make([]bool, len(g.Outputs))
+	)
+
+	for _, input := range g.Inputs {
+		for _, c := range combinationsOf(input.Content.Parameters) {
+			name := c.Name()
+
+			sub, ok := index[name]
+			if !ok {
+				sub = &group{Name: name}
+				index[name] = sub
+				groups = append(groups, sub)
+
+				for i, output := range g.Outputs {
+					if c.Matches(output) {
+						used[i] = true
+						sub.Outputs = append(sub.Outputs, c.Apply(output))
+					}
+				}
+			}
+
+			sub.Inputs = append(sub.Inputs, c.Apply(input))
+		}
+	}
+
+	for i, output := range g.Outputs {
+		if !used[i] {
+			return test.Test{}, NoInputsError{[]ContentEnvelope{output}}
+		}
+	}
+
+	t := test.New(g.Name)
+
+	for _, sub := range groups {
+		if len(sub.Outputs) == 0 {
+			return test.Test{}, fmt.Errorf(
+				"%w for %s",
+				NoOutputsError{sub.Inputs},
+				sub.Name,
+			)
+		}
+
+		x, err := buildTest(sub)
+		if err != nil {
+			return test.Test{}, err
+		}
+
+		if sub.Name != "" {
+			t.SubTests = append(t.SubTests, x)
+			continue
+		}
+
+		t.Skip = t.Skip || x.Skip
+		t.SubTests = append(t.SubTests, x.SubTests...)
+		t.Assertions = append(t.Assertions, x.Assertions...)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,80 @@
+package loader
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/dogmatiq/aureus/internal/test"
+)
+
+// isPipeline returns true if any of the group's outputs has a pipeline stage.
+func (g *group) isPipeline() bool {
+	return slices.ContainsFunc(
+		g.Outputs,
+		func(env ContentEnvelope) bool {
+			return env.Content.Stage != ""
+		},
+	)
+}
+
+// buildPipelineTest builds a test for a group with outputs that are stages of
+// a pipeline.
+//
+// The group must have a single input, and every output must have a distinct
+// stage. The stages are run in the order that the outputs were loaded. The
+// input of the first stage is the group's input, and the input of each later
+// stage is the expected output of the stage before it, which the runner
+// replaces with the actual output of that stage.
+func buildPipelineTest(g *group) (test.Test, error) {
+	if len(g.Inputs) != 1 {
+		return test.Test{}, fmt.Errorf(
+			"input loaded from %s is one of several inputs to a pipeline, which must have exactly one input",
+			location(g.Inputs[1], true),
+		)
+	}
+
+	t := test.New(g.Name, test.WithPipeline(true))
+	input := g.Inputs[0]
+
+	for i, output := range g.Outputs {
+		if output.Content.Stage == "" {
+			return test.Test{}, fmt.Errorf(
+				"output loaded from %s must have a stage because it is part of a pipeline",
+				location(output, true),
+			)
+		}
+
+		for _, x := range g.Outputs[:i] {
+			if x.Content.Stage == output.Content.Stage {
+				return test.Test{}, fmt.Errorf(
+					"output loaded from %s has the same stage (%q) as the output loaded from %s",
+					location(output, true),
+					output.Content.Stage,
+					location(x, true),
+				)
+			}
+		}
+
+		t.SubTests = append(
+			t.SubTests,
+			test.New(
+				output.Content.Stage,
+				test.WithSkip(input.Skip || output.Skip),
+				test.WithAssertions(
+					test.Assertion{
+						Input:  input.AsTestContent(),
+						Output: output.AsTestContent(),
+					},
+				),
+			),
+		)
+
+		// The expected output of this stage is the input to the next, but
+		// skipping this stage does not skip the stages that follow it.
+		input = output
+		input.Content.Role = Input
+		input.Skip = g.Inputs[0].Skip
+	}
+
+	return t, nil
+}
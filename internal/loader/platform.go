@@ -0,0 +1,384 @@
+package loader
+
+//go:generate go run ./internal/platformgen -o platform_list.go
+
+import (
+	"fmt"
+	"go/build"
+	"go/version"
+	"maps"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Platform describes the environments in which content applies. It is used to
+// provide variants of expected output that differ between operating systems,
+// architectures or Go versions.
+//
+// The zero value applies to all environments.
+type Platform struct {
+	// GOOS is the operating system to which the content applies, such as
+	// "linux" or "windows". If it is empty the content applies to all
+	// operating systems.
+	GOOS string
+
+	// GOARCH is the architecture to which the content applies, such as "amd64"
+	// or "arm64". If it is empty the content applies to all architectures.
+	GOARCH string
+
+	// Go is a constraint on the version of Go to which the content applies,
+	// such as "1.25", ">=1.25" or "<1.25". A version without an operator is
+	// equivalent to ">=". If it is empty the content applies to all versions.
+	Go string
+}
+
+const (
+	goosAttr   = "goos"
+	goarchAttr = "goarch"
+	goAttr     = "go"
+)
+
+// ExtractPlatform removes the "goos", "goarch" and "go" attributes from attrs
+// and returns them as a [Platform].
+func ExtractPlatform(attrs map[string]string) Platform {
+	p := Platform{
+		GOOS:   attrs[goosAttr],
+		GOARCH: attrs[goarchAttr],
+		Go:     attrs[goAttr],
+	}
+
+	delete(attrs, goosAttr)
+	delete(attrs, goarchAttr)
+	delete(attrs, goAttr)
+
+	return p
+}
+
+// IsZero returns true if p applies to all environments.
+func (p Platform) IsZero() bool {
+	return p == Platform{}
+}
+
+// specificity returns the number of constraints in p.
+func (p Platform) specificity() int {
+	n := 0
+	for _, v := range []string{p.GOOS, p.GOARCH, p.Go} {
+		if v != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// String returns a human-readable description of the constraints in p.
+func (p Platform) String() string {
+	var parts []string
+	if p.GOOS != "" {
+		parts = append(parts, goosAttr+"="+p.GOOS)
+	}
+	if p.GOARCH != "" {
+		parts = append(parts, goarchAttr+"="+p.GOARCH)
+	}
+	if p.Go != "" {
+		parts = append(parts, goAttr+"="+p.Go)
+	}
+	if len(parts) == 0 {
+		return "no platform constraints"
+	}
+	return strings.Join(parts, " ")
+}
+
+// validate returns an error if there is no environment to which p applies.
+func (p Platform) validate() error {
+	if p.GOOS != "" && !slices.ContainsFunc(knownPlatforms, func(x [2]string) bool { return x[0] == p.GOOS }) {
+		return fmt.Errorf("%q is not a known GOOS", p.GOOS)
+	}
+
+	if p.GOARCH != "" && !slices.ContainsFunc(knownPlatforms, func(x [2]string) bool { return x[1] == p.GOARCH }) {
+		return fmt.Errorf("%q is not a known GOARCH", p.GOARCH)
+	}
+
+	if p.GOOS != "" && p.GOARCH != "" && !slices.Contains(knownPlatforms, [2]string{p.GOOS, p.GOARCH}) {
+		return fmt.Errorf("%s/%s is not a known platform", p.GOOS, p.GOARCH)
+	}
+
+	if p.Go != "" {
+		if _, _, err := parseGoConstraint(p.Go); err != nil {
+			return err
+		}
+
+		if p.goNever() {
+			return fmt.Errorf("%q does not apply to any version of Go", p.Go)
+		}
+	}
+
+	return nil
+}
+
+// minGoVersion is the earliest version of Go.
+const minGoVersion = "go1"
+
+// goAlways returns true if p's Go version constraint applies to every version
+// of Go.
+func (p Platform) goAlways() bool {
+	if p.Go == "" {
+		return true
+	}
+
+	op, v, err := parseGoConstraint(p.Go)
+	if err != nil {
+		return false
+	}
+
+	c := version.Compare(v, minGoVersion)
+	return (op == ">=" && c <= 0) || (op == ">" && c < 0)
+}
+
+// goNever returns true if p's Go version constraint does not apply to any
+// version of Go.
+func (p Platform) goNever() bool {
+	if p.Go == "" {
+		return false
+	}
+
+	op, v, err := parseGoConstraint(p.Go)
+	if err != nil {
+		return false
+	}
+
+	c := version.Compare(v, minGoVersion)
+	return (op == "<" && c <= 0) || (op == "<=" && c < 0) || (op == "=" && c < 0)
+}
+
+// covers returns true if p applies to every environment to which q applies.
+func (p Platform) covers(q Platform) bool {
+	if p.GOOS != "" && p.GOOS != q.GOOS {
+		return false
+	}
+
+	if p.GOARCH != "" && p.GOARCH != q.GOARCH {
+		return false
+	}
+
+	return p.goAlways() || p.Go == q.Go
+}
+
+// matches returns true if p applies to the given environment.
+func (p Platform) matches(env environment) bool {
+	if p.GOOS != "" && p.GOOS != env.GOOS {
+		return false
+	}
+
+	if p.GOARCH != "" && p.GOARCH != env.GOARCH {
+		return false
+	}
+
+	if p.Go != "" {
+		op, v, err := parseGoConstraint(p.Go)
+		if err != nil {
+			return false
+		}
+
+		// Unless the constraint includes a patch version, compare only the
+		// language version of the environment, such that "1.25" includes
+		// "1.25.1", etc.
+		current := env.Go
+		if version.Lang(v) == v {
+			current = version.Lang(current)
+		}
+
+		c := version.Compare(current, v)
+
+		switch op {
+		case ">=":
+			return c >= 0
+		case ">":
+			return c > 0
+		case "<=":
+			return c <= 0
+		case "<":
+			return c < 0
+		case "=":
+			return c == 0
+		}
+	}
+
+	return true
+}
+
+// parseGoConstraint parses a Go version constraint, returning the operator and
+// the version in "go1.x" form.
+func parseGoConstraint(c string) (op, v string, err error) {
+	op = ">="
+	v = c
+
+	for _, x := range []string{">=", "<=", ">", "<", "="} {
+		if s, ok := strings.CutPrefix(c, x); ok {
+			op, v = x, s
+			break
+		}
+	}
+
+	v = "go" + strings.TrimPrefix(v, "go")
+	if !version.IsValid(v) {
+		return "", "", fmt.Errorf("%q is not a valid Go version constraint", c)
+	}
+
+	return op, v, nil
+}
+
+// environment is the environment in which the tests are run.
+type environment struct {
+	GOOS, GOARCH, Go string
+}
+
+// currentEnvironment returns the environment in which the tests are run.
+func currentEnvironment() environment {
+	v := runtime.Version()
+	if !version.IsValid(v) {
+		// Development builds of Go report a version such as "devel +abcdef",
+		// in which case we fall back to the latest release tag, which
+		// identifies the language version.
+		tags := build.Default.ReleaseTags
+		v = tags[len(tags)-1]
+	}
+
+	return environment{runtime.GOOS, runtime.GOARCH, v}
+}
+
+// selectVariants replaces each set of platform-specific variants of the same
+// output with the variant that applies to env.
+//
+// Outputs are variants of each other if they differ only in their [Platform].
+// The most specific variant that applies to env is selected, falling back to
+// the variant with no platform constraints. If no variant applies, the first
+// variant is retained but skipped.
+//
+// It returns an error if any variant could not be selected in any environment,
+// either because its constraints can not be satisfied, or because another
+// variant that is at least as specific applies to every environment that it
+// does.
+func selectVariants(outputs []ContentEnvelope, env environment) ([]ContentEnvelope, error) {
+	var (
+		slots = map[string][]ContentEnvelope{}
+		keys  []string
+	)
+
+	for _, out := range outputs {
+		if err := out.Content.Platform.validate(); err != nil {
+			return nil, fmt.Errorf(
+				"output loaded from %s is never selected: %w",
+				location(out, true),
+				err,
+			)
+		}
+
+		k := variantKey(out.Content)
+		if _, ok := slots[k]; !ok {
+			keys = append(keys, k)
+		}
+		slots[k] = append(slots[k], out)
+	}
+
+	var selected []ContentEnvelope
+
+	for _, k := range keys {
+		variants := slots[k]
+
+		if !slices.ContainsFunc(variants, func(x ContentEnvelope) bool {
+			return !x.Content.Platform.IsZero()
+		}) {
+			selected = append(selected, variants...)
+			continue
+		}
+
+		for i, a := range variants {
+			for _, b := range variants[:i] {
+				if a.Content.Platform == b.Content.Platform {
+					return nil, fmt.Errorf(
+						"output loaded from %s is never selected: it has the same platform constraints as %s",
+						location(a, true),
+						location(b, true),
+					)
+				}
+			}
+		}
+
+		for _, a := range variants {
+			for _, b := range variants {
+				pa, pb := a.Content.Platform, b.Content.Platform
+
+				if pa != pb && pb.covers(pa) && pb.specificity() >= pa.specificity() {
+					return nil, fmt.Errorf(
+						"output loaded from %s is never selected: the variant loaded from %s is at least as specific, and applies to every environment that it does",
+						location(a, true),
+						location(b, true),
+					)
+				}
+			}
+		}
+
+		best := -1
+		ambiguous := false
+
+		for i, v := range variants {
+			if !v.Content.Platform.matches(env) {
+				continue
+			}
+
+			if best == -1 {
+				best = i
+				continue
+			}
+
+			a := v.Content.Platform.specificity()
+			b := variants[best].Content.Platform.specificity()
+
+			if a > b {
+				best = i
+				ambiguous = false
+			} else if a == b {
+				ambiguous = true
+			}
+		}
+
+		if ambiguous {
+			return nil, fmt.Errorf(
+				"output loaded from %s is ambiguous: more than one variant applies to %s/%s (%s)",
+				location(variants[best], true),
+				env.GOOS,
+				env.GOARCH,
+				env.Go,
+			)
+		}
+
+		if best == -1 {
+			v := variants[0]
+			v.Skip = true
+			selected = append(selected, v)
+		} else {
+			selected = append(selected, variants[best])
+		}
+	}
+
+	return selected, nil
+}
+
+// variantKey returns a key that is the same for all content that are variants
+// of each other.
+func variantKey(c Content) string {
+	var w strings.Builder
+
+	fmt.Fprintf(&w, "%q %q %q", c.Name, c.Caption, c.Language)
+
+	for _, k := range slices.Sorted(maps.Keys(c.Attributes)) {
+		fmt.Fprintf(&w, " %q=%q", k, c.Attributes[k])
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(c.Parameters)) {
+		fmt.Fprintf(&w, " %q=%q", k, c.Parameters[k])
+	}
+
+	return w.String()
+}
@@ -0,0 +1,104 @@
+package txtarloader
+
+import (
+	"bytes"
+	"maps"
+	"strings"
+
+	"golang.org/x/tools/txtar"
+)
+
+var newline = []byte("\n")
+
+// section is a file within a txtar archive, along with its location within the
+// archive.
+type section struct {
+	txtar.File
+
+	// Line is the line number of the file's marker line.
+	Line int
+
+	// The half-open range [Begin, End) is the file's data within the archive,
+	// given in bytes.
+	Begin, End int64
+}
+
+// parseArchive parses a txtar archive, returning its comment and the sections
+// that it contains.
+func parseArchive(source []byte) (comment []byte, sections []section) {
+	archive := txtar.Parse(source)
+	offset := len(archive.Comment)
+
+	for _, f := range archive.Files {
+		// Skip over the marker line, which may contain additional whitespace
+		// around the name.
+		offset += bytes.IndexByte(source[offset:], '\n') + 1
+
+		// The final file's data may have had a newline appended to it by
+		// txtar.Parse() if it was missing from the source.
+		end := min(offset+len(f.Data), len(source))
+
+		sections = append(
+			sections,
+			section{
+				File:  f,
+				Line:  bytes.Count(source[:offset], newline),
+				Begin: int64(offset),
+				End:   int64(end),
+			},
+		)
+
+		offset = end
+	}
+
+	return archive.Comment, sections
+}
+
+// parseComment parses the comment of a txtar archive.
+//
+// Lines that begin with an "@" are attributes of the form "@key=value" or
+// "@flag". All other text is treated as a description of the test.
+func parseComment(comment []byte) (desc string, attrs map[string]string) {
+	var lines []string
+
+	for line := range strings.Lines(string(comment)) {
+		attr, ok := strings.CutPrefix(strings.TrimSpace(line), "@")
+		if !ok {
+			lines = append(lines, line)
+			continue
+		}
+
+		if attrs == nil {
+			attrs = map[string]string{}
+		}
+
+		if pos := strings.Index(attr, "="); pos != -1 {
+			attrs[attr[:pos]] = attr[pos+1:]
+		} else {
+			attrs[attr] = ""
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "")), attrs
+}
+
+// mergeAttributes returns the union of the archive-level attributes and the
+// attributes of a specific file. The file's attributes take precedence.
+func mergeAttributes(archive, file map[string]string) map[string]string {
+	if len(archive) == 0 {
+		return file
+	}
+
+	attrs := maps.Clone(archive)
+	maps.Copy(attrs, file)
+	return attrs
+}
+
+// encodeSection ensures that blessed output ends with a newline, so that it
+// does not run into the marker line of the next file in the archive.
+func encodeSection(data []byte) []byte {
+	if len(data) != 0 && !bytes.HasSuffix(data, newline) {
+		data = append(data, '\n')
+	}
+	return data
+}
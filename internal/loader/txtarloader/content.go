@@ -0,0 +1,29 @@
+package txtarloader
+
+import (
+	"github.com/dogmatiq/aureus/internal/loader"
+)
+
+// A ContentLoader is a function that returns content obtained from a file
+// within a txtar archive.
+//
+// name is the name of the file within the archive, and data is its content.
+//
+// If the returned content's role is [loader.NoRole], it is ignored.
+type ContentLoader func(name string, data []byte) (loader.Content, error)
+
+// LoadContent is the default [ContentLoader] implementation.
+//
+// It identifies test files using [loader.ParseFileName], such that files named
+// "input.json" and "output.json", or "<group>.input" and "<group>.output" are
+// treated as test inputs and outputs.
+func LoadContent(name string, data []byte) (loader.Content, error) {
+	content := loader.ParseFileName(name)
+	if content.Role == loader.NoRole {
+		return loader.Content{}, nil
+	}
+
+	content.Data = data
+
+	return content, nil
+}
@@ -0,0 +1,5 @@
+// Package txtarloader loads [test.Test] values from txtar archives containing
+// test inputs and expected outputs.
+//
+// See https://pkg.go.dev/golang.org/x/tools/txtar.
+package txtarloader
@@ -0,0 +1,111 @@
+package txtarloader
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/dogmatiq/aureus/internal/loader"
+	"github.com/dogmatiq/aureus/internal/rootfs"
+	"github.com/dogmatiq/aureus/internal/test"
+)
+
+// Loader loads [test.Test] values from txtar archives containing test inputs
+// and expected outputs.
+type Loader struct {
+	options loadOptions
+}
+
+// NewLoader returns a new [Loader], which loads golden file tests from the
+// filesystem.
+func NewLoader(options ...LoadOption) *Loader {
+	l := &Loader{
+		options: loadOptions{
+			FS:          rootfs.FS,
+			Recurse:     true,
+			LoadContent: LoadContent,
+		},
+	}
+
+	for _, opt := range options {
+		opt(&l.options)
+	}
+
+	return l
+}
+
+// Load returns a test built from files in the given directory.
+//
+// Each file with a ".txtar" extension produces a single test. Any directory or
+// file that begins with an underscore produces a test that is marked as
+// skipped.
+func (l *Loader) Load(dir string, options ...LoadOption) (test.Test, error) {
+	opts := l.options
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	return loader.LoadDir(
+		opts.FS,
+		dir,
+		opts.Recurse,
+		func(builder *loader.TestBuilder, fsys fs.FS, filePath string) error {
+			return loadFile(builder, opts, filePath)
+		},
+	)
+}
+
+func loadFile(builder *loader.TestBuilder, opts loadOptions, filePath string) error {
+	name := path.Base(filePath)
+	name, skip := strings.CutPrefix(name, "_")
+	name, ok := strings.CutSuffix(name, ".txtar")
+	if !ok {
+		return nil
+	}
+
+	source, err := fs.ReadFile(opts.FS, filePath)
+	if err != nil {
+		return err
+	}
+
+	comment, sections := parseArchive(source)
+	desc, attrs := parseComment(comment)
+
+	var b loader.TestBuilder
+
+	for _, s := range sections {
+		c, err := opts.LoadContent(s.Name, s.Data)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", filePath, s.Line, err)
+		}
+
+		c.Attributes = mergeAttributes(attrs, c.Attributes)
+
+		if err := b.AddContent(
+			loader.ContentEnvelope{
+				File:    filePath,
+				Line:    s.Line,
+				Begin:   s.Begin,
+				End:     s.End,
+				Encode:  encodeSection,
+				Content: c,
+			},
+		); err != nil {
+			return err
+		}
+	}
+
+	t, err := b.BuildTest(
+		name,
+		test.WithDescription(desc),
+		test.WithSkip(skip),
+	)
+	if err != nil {
+		return err
+	}
+
+	builder.AddTest(t)
+
+	return nil
+}
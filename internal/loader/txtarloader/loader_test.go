@@ -0,0 +1,13 @@
+package txtarloader_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/aureus/internal/loader/internal/loadertest"
+	. "github.com/dogmatiq/aureus/internal/loader/txtarloader"
+)
+
+func TestLoader(t *testing.T) {
+	loader := NewLoader()
+	loadertest.Run(t, loader.Load)
+}
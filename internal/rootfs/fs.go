@@ -39,6 +39,11 @@ func (rootFS) Stat(name string) (fs.FileInfo, error) {
 	return fs.Stat(underlying, name)
 }
 
+// WriteFile replaces the content of the named file, creating it if necessary.
+func (rootFS) WriteFile(name string, data []byte) error {
+	return os.WriteFile(name, data, 0644)
+}
+
 func normalizePath(name string) (string, error) {
 	name, err := filepath.Abs(name)
 	if err != nil {
@@ -1,9 +1,10 @@
 package runner
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
-	"io"
-	"os"
+	"io/fs"
 
 	"github.com/dogmatiq/aureus/internal/test"
 )
@@ -26,124 +27,106 @@ const (
 	BlessDisabled
 )
 
-func bless(output test.Content, blessed []byte) error {
-	// TODO: Tests are loaded using an [fs.FS], so the file system is not
-	// necessarily the host file system.
-	//
-	// Ultimately, we probably need to make it the loader's responsibility to
-	// bless tests, since it is the loader that knows where the tests came from.
+// edit is a record of a change to the size of a file that occurred when
+// blessing a test.
+type edit struct {
+	// Begin and End are the offsets within the original file of the content
+	// that was replaced.
+	Begin, End int64
 
-	f, err := os.OpenFile(output.File, os.O_RDWR, 0644)
-	if err != nil {
-		return fmt.Errorf("unable to open file containing expected output: %w", err)
-	}
-	defer f.Close()
+	// Delta is the change in size, in bytes.
+	Delta int64
+}
 
-	if output.IsEntireFile() {
-		n, err := f.Write(blessed)
-		if err != nil {
-			return err
-		}
-		return f.Truncate(int64(n))
+// bless replaces the content of output within its file with blessed.
+//
+// The offsets of content within a file are determined when the file is loaded,
+// so they must be adjusted to account for any prior blessing of content that
+// appears earlier within the same file, or prior blessing of the same content.
+// Prior edits are applied to the content that was last written, rather than
+// re-reading the file, as r.FS does not necessarily reflect the writes made by
+// r.WriteFile.
+func (r *Runner[T]) bless(output test.Content, blessed []byte) error {
+	if r.WriteFile == nil {
+		return errors.New("the file system is read-only")
 	}
 
-	if err := resize(f, output, blessed); err != nil {
-		return fmt.Errorf("unable to resize expected output: %w", err)
+	if output.Encode != nil {
+		blessed = output.Encode(blessed)
 	}
 
-	if _, err := f.Seek(output.Begin, io.SeekStart); err != nil {
-		return err
+	if output.IsEntireFile() {
+		return r.WriteFile(output.File, blessed)
 	}
 
-	_, err = f.Write(blessed)
-	return err
-}
-
-func resize(f *os.File, output test.Content, blessed []byte) error {
-	sizeWithinFile := output.End - output.Begin
-	sizeBlessed := int64(len(blessed))
-
-	if sizeWithinFile == sizeBlessed {
-		return nil
+	data, ok := r.written[output.File]
+	if !ok {
+		var err error
+		data, err = fs.ReadFile(r.FS, output.File)
+		if err != nil {
+			return fmt.Errorf("unable to read file containing expected output: %w", err)
+		}
 	}
 
-	sizeBefore, err := fileSize(f)
-	if err != nil {
-		return err
+	edits := r.edits[output.File]
+	prior := -1
+	var shift int64
+
+	for i, e := range edits {
+		switch {
+		case e.Begin == output.Begin && e.End == output.End:
+			prior = i
+		case e.End <= output.Begin:
+			shift += e.Delta
+		case e.Begin >= output.End:
+			// The edit is after the output, so it does not affect its offsets.
+		default:
+			return fmt.Errorf("expected output overlaps other blessed content within %s", output.File)
+		}
 	}
 
-	sizeAfter := sizeBefore - sizeWithinFile + sizeBlessed
+	begin := output.Begin + shift
+	end := output.End + shift
 
-	op := shrink
-	if sizeAfter > sizeBefore {
-		op = grow
+	if prior != -1 {
+		end += edits[prior].Delta
 	}
 
-	return op(
-		f,
-		output.End,
-		sizeBefore,
-		sizeAfter,
-	)
-}
-
-func shrink(w *os.File, endOfContent, fileLengthBefore, fileLengthAfter int64) error {
-	moveDistance := fileLengthBefore - fileLengthAfter
-	buf := make([]byte, 4096)
-
-	for {
-		n, err := w.ReadAt(buf, endOfContent)
-		if err != nil && err != io.EOF {
-			return err
-		}
-
-		if n > 0 {
-			if _, err := w.WriteAt(buf[:n], endOfContent-moveDistance); err != nil {
-				return err
-			}
-
-			endOfContent += int64(n)
-		}
-
-		if err == io.EOF {
-			return w.Truncate(fileLengthAfter)
-		}
+	if begin < 0 || end > int64(len(data)) || begin > end {
+		return fmt.Errorf("expected output is no longer within the bounds of %s", output.File)
 	}
-}
 
-func grow(w *os.File, endOfContent, fileLengthBefore, fileLengthAfter int64) error {
-	moveDistance := fileLengthAfter - fileLengthBefore
-	moveLength := fileLengthBefore - endOfContent
-	bufSize := min(moveLength, 4096)
-	buf := make([]byte, bufSize)
+	var w bytes.Buffer
+	w.Grow(len(data) - int(end-begin) + len(blessed))
+	w.Write(data[:begin])
+	w.Write(blessed)
+	w.Write(data[end:])
 
-	// (1) move the partial chunk that doesn't fill the entire buffer first.
-	chunkSize := moveLength % bufSize
-	if chunkSize == 0 {
-		chunkSize = bufSize
+	if err := r.WriteFile(output.File, w.Bytes()); err != nil {
+		return err
 	}
 
-	for cursor := fileLengthBefore - chunkSize; cursor >= endOfContent; cursor -= chunkSize {
-		_, err := w.ReadAt(buf[:chunkSize], cursor)
-		if err != nil {
-			return err
-		}
-
-		if _, err := w.WriteAt(buf[:chunkSize], cursor+moveDistance); err != nil {
-			return err
-		}
+	if r.edits == nil {
+		r.edits = map[string][]edit{}
+		r.written = map[string][]byte{}
+	}
 
-		// (2) move the rest of the content in chunks of the full buffer size.
-		chunkSize = bufSize
+	r.written[output.File] = w.Bytes()
+
+	delta := int64(len(blessed)) - (output.End - output.Begin)
+
+	if prior != -1 {
+		edits[prior].Delta = delta
+	} else {
+		r.edits[output.File] = append(
+			edits,
+			edit{
+				Begin: output.Begin,
+				End:   output.End,
+				Delta: delta,
+			},
+		)
 	}
 
 	return nil
 }
-
-func fileSize(f *os.File) (int64, error) {
-	stat, err := f.Stat()
-	if err != nil {
-		return 0, fmt.Errorf("unable to determine file size of %s: %w", f.Name(), err)
-	}
-	return stat.Size(), nil
-}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"iter"
 	"os"
 
 	"github.com/dogmatiq/aureus/internal/test"
@@ -22,7 +23,59 @@ type Input interface {
 
 	// Attributes returns a set of key-value pairs that provide additional
 	// loader-specific information about the input.
-	Attributes() map[string]string
+	Attributes() Attributes
+
+	// Location returns the location of the input within the file from which
+	// it was loaded.
+	Location() Location
+
+	// Group returns the name of the group to which the input belongs, or an
+	// empty string if it does not belong to a named group.
+	Group() string
+
+	// Caption returns an optional disambiguating name, title or short
+	// description of the input.
+	Caption() string
+
+	// Headings returns the document headings under which the input appears,
+	// outermost first. It returns nil if the loader does not support headings.
+	Headings() []string
+
+	// TestName returns the full name of the Go test that is making the
+	// assertion, as per [testing.T.Name].
+	TestName() string
+
+	// Fixtures returns the fixtures that provide additional context for the
+	// input, such as a schema or configuration file.
+	Fixtures() []Fixture
+
+	// Name returns the name of the input, if any.
+	Name() string
+
+	// Named returns the named input with the given name. Named inputs are
+	// used together within a single test, such as the "old" and "new" inputs
+	// of a migration.
+	//
+	// ok is false if the test has no input with the given name.
+	Named(name string) (_ Input, ok bool)
+
+	// NamedInputs returns an iterator over the test's named inputs, in the
+	// order that they were loaded.
+	NamedInputs() iter.Seq2[string, Input]
+}
+
+// RoundTripOutput is implemented by the [Output] passed to an
+// [OutputGenerator]. It allows the generator to register an inverse generator
+// that is used to check the output it produces.
+//
+// The generator is not an [OutputGenerator] so that the interface can be used
+// with any test type.
+type RoundTripOutput[T any] interface {
+	Output
+
+	// SetRoundTrip sets the generator that converts the actual output back
+	// into the input.
+	SetRoundTrip(func(T, Input, Output) error)
 }
 
 // Output is an interface for producing the output for a test.
@@ -33,45 +86,188 @@ type Output interface {
 	// e.g. "json", "yaml", etc.
 	Language() string
 
+	// Name returns the name of the output, if any. Names distinguish between
+	// several outputs produced from the same input, such as "stdout" and
+	// "stderr".
+	Name() string
+
+	// Stage returns the name of the pipeline stage that produces the output,
+	// if any. Within a pipeline, the input to each stage is the actual output
+	// of the previous stage.
+	Stage() string
+
 	// Attributes returns a set of key-value pairs that provide additional
 	// loader-specific information about the expected output.
-	Attributes() map[string]string
+	Attributes() Attributes
+
+	// Location returns the location of the expected output within the file from
+	// which it was loaded.
+	Location() Location
+
+	// Group returns the name of the group to which the expected output belongs,
+	// or an empty string if it does not belong to a named group.
+	Group() string
+
+	// Caption returns an optional disambiguating name, title or short
+	// description of the expected output.
+	Caption() string
+
+	// Headings returns the document headings under which the expected output
+	// appears, outermost first. It returns nil if the loader does not support
+	// headings.
+	Headings() []string
+
+	// TestName returns the full name of the Go test that is making the
+	// assertion, as per [testing.T.Name].
+	TestName() string
+}
+
+// Attributes is a set of key-value pairs that provide additional
+// loader-specific information about test content.
+type Attributes = test.Attributes
+
+// Fixture is additional content that provides context for a test's input.
+type Fixture struct {
+	// Name is the name of the fixture, if any.
+	Name string
+
+	// Location is the location of the fixture within the file from which it
+	// was loaded.
+	Location Location
+
+	// Language is the language of the fixture, if known, e.g. "json", "sql",
+	// etc.
+	Language string
+
+	// Attributes is a set of key-value pairs that provide additional
+	// loader-specific information about the fixture.
+	Attributes Attributes
+
+	// Data is the content of the fixture.
+	Data []byte
+}
+
+// Location describes where a test's input or expected output was loaded from.
+type Location = test.Location
+
+type metaData struct {
+	meta     test.ContentMetaData
+	testName string
+}
+
+func (m *metaData) Language() string {
+	return m.meta.Language
+}
+
+func (m *metaData) Name() string {
+	return m.meta.Name
+}
+
+func (m *metaData) Stage() string {
+	return m.meta.Stage
+}
+
+func (m *metaData) Attributes() Attributes {
+	return m.meta.Attributes
+}
+
+func (m *metaData) Location() Location {
+	return m.meta.Location()
+}
+
+func (m *metaData) Group() string {
+	return m.meta.Group
+}
+
+func (m *metaData) Caption() string {
+	return m.meta.Caption
+}
+
+func (m *metaData) Headings() []string {
+	return m.meta.Headings
+}
+
+func (m *metaData) TestName() string {
+	return m.testName
 }
 
 type input struct {
 	io.Reader
-	meta test.ContentMetaData
+	metaData
+	assertion test.Assertion
+}
+
+func newInput(testName string, c test.Content, a test.Assertion) *input {
+	return &input{
+		bytes.NewReader(c.Data),
+		metaData{c.ContentMetaData, testName},
+		a,
+	}
 }
 
-func (i *input) Language() string {
-	return i.meta.Language
+func (i *input) Fixtures() []Fixture {
+	return fixturesOf(i.assertion)
 }
 
-func (i *input) Attributes() map[string]string {
-	return i.meta.Attributes
+func (i *input) Named(name string) (Input, bool) {
+	for n, in := range i.NamedInputs() {
+		if n == name {
+			return in, true
+		}
+	}
+	return nil, false
 }
 
-type output struct {
+func (i *input) NamedInputs() iter.Seq2[string, Input] {
+	return func(yield func(string, Input) bool) {
+		for _, c := range i.assertion.NamedInputs {
+			if !yield(c.Name, newInput(i.testName, c, i.assertion)) {
+				return
+			}
+		}
+	}
+}
+
+type output[T TestingT[T]] struct {
 	io.Writer
-	meta test.ContentMetaData
+	metaData
+	roundTrip OutputGenerator[T]
 }
 
-func (o *output) Language() string {
-	return o.meta.Language
+func (o *output[T]) SetRoundTrip(g func(T, Input, Output) error) {
+	o.roundTrip = g
 }
 
-func (o *output) Attributes() map[string]string {
-	return o.meta.Attributes
+// fixturesOf returns the fixtures of the given assertion.
+func fixturesOf(a test.Assertion) []Fixture {
+	var fixtures []Fixture
+
+	for _, c := range a.Fixtures {
+		fixtures = append(
+			fixtures,
+			Fixture{
+				Name:       c.Name,
+				Location:   c.Location(),
+				Language:   c.Language,
+				Attributes: c.Attributes,
+				Data:       c.Data,
+			},
+		)
+	}
+
+	return fixtures
 }
 
+// generateOutput writes the output produced by gen to a temporary file. It
+// returns the file, and the round-trip generator registered by gen, if any.
 func generateOutput[T TestingT[T]](
 	t T,
 	gen OutputGenerator[T],
-	in, out test.Content,
-) (_ *os.File, err error) {
+	a test.Assertion,
+) (_ *os.File, roundTrip OutputGenerator[T], err error) {
 	f, err := os.CreateTemp("", "aureus-")
 	if err != nil {
-		return nil, fmt.Errorf("unable to create temporary file: %w", err)
+		return nil, nil, fmt.Errorf("unable to create temporary file: %w", err)
 	}
 	defer func() {
 		if err != nil {
@@ -80,23 +276,22 @@ func generateOutput[T TestingT[T]](
 		}
 	}()
 
+	out := &output[T]{
+		Writer:   f,
+		metaData: metaData{a.Output.ContentMetaData, t.Name()},
+	}
+
 	if err := gen(
 		t,
-		&input{
-			Reader: bytes.NewReader(in.Data),
-			meta:   in.ContentMetaData,
-		},
-		&output{
-			Writer: f,
-			meta:   out.ContentMetaData,
-		},
+		newInput(t.Name(), a.Input, a),
+		out,
 	); err != nil {
-		return nil, fmt.Errorf("unable to generate output: %w", err)
+		return nil, nil, fmt.Errorf("unable to generate output: %w", err)
 	}
 
 	if _, err := f.Seek(0, io.SeekStart); err != nil {
-		return nil, fmt.Errorf("unable to seek to beginning of output file: %w", err)
+		return nil, nil, fmt.Errorf("unable to seek to beginning of output file: %w", err)
 	}
 
-	return f, nil
+	return f, out.roundTrip, nil
 }
@@ -2,10 +2,14 @@ package runner
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/dogmatiq/aureus/internal/diff"
@@ -20,6 +24,30 @@ type Runner[T TestingT[T]] struct {
 	BlessStrategy   BlessStrategy
 	AssertionFilter func(test.Assertion) bool
 	PackagePath     string
+
+	// FS is the file system from which the tests were loaded. It is used to
+	// read the current content of files when blessing test output.
+	FS fs.FS
+
+	// WriteFile replaces the content of the named file within FS. If it is nil
+	// test output can not be blessed.
+	WriteFile func(name string, data []byte) error
+
+	// Idempotent, if true, causes each assertion to also check that passing
+	// the actual output back through GenerateOutput leaves it unchanged.
+	Idempotent bool
+
+	// Repeat is the number of times to generate the output of each assertion.
+	// If it is greater than one, the test fails if the outputs differ.
+	Repeat int
+
+	// VaryGOMAXPROCS, if true, causes each repetition to be performed with a
+	// different value of [runtime.GOMAXPROCS]. The test fails if it is a
+	// parallel test, or if T does not have a Setenv() method.
+	VaryGOMAXPROCS bool
+
+	edits   map[string][]edit
+	written map[string][]byte
 }
 
 // Run makes the assertions described by all documents within a [TestSuite].
@@ -37,6 +65,20 @@ func (r *Runner[T]) Run(t T, x test.Test) {
 				return
 			}
 
+			if x.Description != "" {
+				logSection(
+					t,
+					"DESCRIPTION",
+					[]byte(x.Description+"\n"),
+					"\x1b[2m",
+				)
+			}
+
+			if x.Pipeline {
+				r.runPipeline(t, x)
+				return
+			}
+
 			for _, s := range x.SubTests {
 				r.Run(t, s)
 			}
@@ -48,46 +90,297 @@ func (r *Runner[T]) Run(t T, x test.Test) {
 	)
 }
 
-func (r *Runner[T]) assert(t T, a test.Assertion) {
+// runPipeline runs the stages of a pipeline test in order, using the actual
+// output of each stage as the input to the next.
+//
+// Once a stage fails, the remaining stages are skipped, as their input is not
+// known to be correct. When a stage is skipped for any other reason, its
+// expected output is used as the input to the next stage.
+func (r *Runner[T]) runPipeline(t T, x test.Test) {
 	t.Helper()
+
+	var (
+		prev   []byte
+		failed bool
+	)
+
+	for i, stage := range x.SubTests {
+		t.Run(
+			stage.Name,
+			func(t T) {
+				t.Helper()
+
+				if failed {
+					t.SkipNow()
+					return
+				}
+
+				if stage.Skip {
+					for _, a := range stage.Assertions {
+						prev = a.Output.Data
+					}
+					t.SkipNow()
+					return
+				}
+
+				for _, a := range stage.Assertions {
+					if i > 0 {
+						a.Input.Data = prev
+					}
+
+					// Use the expected output as the input to the next stage
+					// in case the assertion is skipped by the filter.
+					prev = a.Output.Data
+
+					got, ok := r.assert(t, a)
+					if !ok || t.Failed() {
+						failed = true
+						return
+					}
+
+					prev = got
+				}
+			},
+		)
+	}
+}
+
+// assert makes a single assertion. It returns the actual output, and false if
+// the output could not be generated.
+func (r *Runner[T]) assert(t T, a test.Assertion) (_ []byte, ok bool) {
+	t.Helper()
+	title := "INPUT"
+	if a.Input.File != "" {
+		title = fmt.Sprintf("INPUT (%s)", location(a.Input))
+	}
+
 	logSection(
 		t,
-		fmt.Sprintf("INPUT (%s)", location(a.Input)),
+		title,
 		a.Input.Data,
 		"\x1b[2m",
 	)
 
 	if r.AssertionFilter != nil && !r.AssertionFilter(a) {
 		t.SkipNow()
-		return
+		return nil, false
 	}
 
-	f, err := generateOutput(t, r.GenerateOutput, a.Input, a.Output)
+	f, back, err := generateOutput(t, r.GenerateOutput, a)
 	if err != nil {
 		t.Log(err)
 		t.Fail()
+		return nil, false
+	}
+	defer r.removeOutputFile(t, f)
+
+	got := r.compare(t, "OUTPUT", a.Output, f, true)
+
+	if r.Repeat > 1 {
+		r.repeat(t, a, got)
+	}
+
+	if r.Idempotent {
+		r.idempotence(t, a, got)
+	}
+
+	if back != nil {
+		r.roundTrip(t, a, got, back)
+	} else if a.RoundTrip != nil {
+		t.Log(
+			"round-trip content loaded from",
+			location(*a.RoundTrip),
+			"is not checked because the output generator does not perform a round-trip",
+		)
+		t.Fail()
+	}
+
+	return got, true
+}
+
+// roundTrip asserts that the backward generator reproduces the original input
+// (or the explicit round-trip content, if any) from the actual output.
+func (r *Runner[T]) roundTrip(
+	t T,
+	a test.Assertion,
+	got []byte,
+	back OutputGenerator[T],
+) {
+	t.Helper()
+
+	want, blessable := a.Input, false
+	if a.RoundTrip != nil {
+		want, blessable = *a.RoundTrip, true
+	}
+
+	f, _, err := generateOutput(
+		t,
+		back,
+		test.Assertion{
+			Input: test.Content{
+				ContentMetaData: a.Output.ContentMetaData,
+				Data:            got,
+			},
+			Output:      want,
+			NamedInputs: a.NamedInputs,
+			Fixtures:    a.Fixtures,
+		},
+	)
+	if err != nil {
+		t.Log("round-trip:", err)
+		t.Fail()
 		return
 	}
+	defer r.removeOutputFile(t, f)
+
+	r.compare(t, "ROUND-TRIP", want, f, blessable)
+}
+
+// repeat asserts that generating the output again produces the same output,
+// repeating up to r.Repeat times in total. Only the first output that differs
+// from the original is reported.
+func (r *Runner[T]) repeat(t T, a test.Assertion, got []byte) {
+	t.Helper()
+
+	if r.VaryGOMAXPROCS {
+		defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	}
+
+	first := test.Content{
+		ContentMetaData: a.Output.ContentMetaData,
+		Data:            got,
+	}
+
+	for n := 2; n <= r.Repeat; n++ {
+		if r.VaryGOMAXPROCS {
+			if err := setGOMAXPROCS(t, 1+(n-1)%runtime.NumCPU()); err != nil {
+				t.Log("unable to vary GOMAXPROCS:", err)
+				t.Fail()
+				return
+			}
+		}
+
+		if !r.repeatOnce(t, a, first, n) {
+			return
+		}
+	}
+}
+
+// setGOMAXPROCS sets [runtime.GOMAXPROCS] to n. It also sets the GOMAXPROCS
+// environment variable for the remainder of the test, which is inherited by
+// child processes.
+//
+// GOMAXPROCS is a process-wide setting, so it returns an error if t does not
+// support setting environment variables, or is (or belongs to) a parallel
+// test, as reported by [testing.T.Setenv].
+func setGOMAXPROCS(t any, n int) (err error) {
+	x, ok := t.(interface{ Setenv(key, value string) })
+	if !ok {
+		return fmt.Errorf("%T does not have a Setenv() method", t)
+	}
+
 	defer func() {
-		f.Close()
-		if !t.Failed() {
-			os.Remove(f.Name())
+		if recover() != nil {
+			err = errors.New("it can not be used with parallel tests")
 		}
 	}()
 
-	want := a.Output.Data
+	x.Setenv("GOMAXPROCS", strconv.Itoa(n))
+	runtime.GOMAXPROCS(n)
+
+	return nil
+}
+
+// repeatOnce generates the output for the n'th repetition of an assertion. It
+// returns false if the output differs from the first output.
+func (r *Runner[T]) repeatOnce(t T, a test.Assertion, first test.Content, n int) bool {
+	t.Helper()
+
+	f, _, err := generateOutput(t, r.GenerateOutput, a)
+	if err != nil {
+		t.Log(fmt.Sprintf("repetition #%d:", n), err)
+		t.Fail()
+		return false
+	}
+	defer r.removeOutputFile(t, f)
+
 	got, err := io.ReadAll(f)
 	if err != nil {
 		t.Log("unable to read output file:", err)
+		t.Fail()
+		return false
 	}
 
-	if r.TrimSpace {
-		want = append(bytes.TrimRight(want, "\n"), '\n')
-		got = append(bytes.TrimRight(got, "\n"), '\n')
+	if bytes.Equal(r.normalize(first.Data), r.normalize(got)) {
+		return true
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Log("unable to seek to beginning of output file:", err)
+		t.Fail()
+		return false
+	}
+
+	r.compare(t, fmt.Sprintf("REPETITION #%d", n), first, f, false)
+	return false
+}
+
+// idempotence asserts that generating output from the actual output produces
+// the same output again.
+func (r *Runner[T]) idempotence(t T, a test.Assertion, got []byte) {
+	t.Helper()
+
+	first := test.Content{
+		ContentMetaData: a.Output.ContentMetaData,
+		Data:            got,
 	}
 
+	a.Input.Data = got
+	f, _, err := generateOutput(t, r.GenerateOutput, a)
+	if err != nil {
+		t.Log("idempotence:", err)
+		t.Fail()
+		return
+	}
+	defer r.removeOutputFile(t, f)
+
+	r.compare(t, "IDEMPOTENCE", first, f, false)
+}
+
+// removeOutputFile closes f, and removes it unless the test has failed, in
+// which case it is retained for inspection.
+func (r *Runner[T]) removeOutputFile(t T, f *os.File) {
+	f.Close()
+	if !t.Failed() {
+		os.Remove(f.Name())
+	}
+}
+
+// compare compares the content of f to the expected content, and logs either
+// the content or the differences in a section with the given title.
+//
+// If blessable is true the expected content is replaced with the actual
+// content when blessing is enabled. It returns the actual content.
+func (r *Runner[T]) compare(
+	t T,
+	title string,
+	expected test.Content,
+	f *os.File,
+	blessable bool,
+) []byte {
+	t.Helper()
+
+	want := expected.Data
+	got, err := io.ReadAll(f)
+	if err != nil {
+		t.Log("unable to read output file:", err)
+	}
+
+	want = r.normalize(want)
+	got = r.normalize(got)
+
 	diff := diff.ColorDiff(
-		location(a.Output),
+		location(expected),
 		want,
 		f.Name(),
 		got,
@@ -99,18 +392,26 @@ func (r *Runner[T]) assert(t T, a test.Assertion) {
 	}
 
 	if len(diff) == 0 {
+		heading := title
+		if expected.File != "" {
+			heading = fmt.Sprintf("%s (%s)", title, location(expected))
+		}
+
 		logSection(
 			t,
-			fmt.Sprintf("OUTPUT (%s)", location(a.Output)),
-			a.Output.Data,
+			heading,
+			expected.Data,
 			"\x1b[33;2m",
 			messages...,
 		)
-		return
+		return got
 	}
 
-	switch r.BlessStrategy {
-	case BlessAvailable:
+	switch {
+	case !blessable:
+		t.Fail()
+
+	case r.BlessStrategy == BlessAvailable:
 		t.Fail()
 		messages = append(
 			messages,
@@ -118,14 +419,14 @@ func (r *Runner[T]) assert(t T, a test.Assertion) {
 				"    \x1b[2m"+r.goTestCommand(t)+" -aureus.bless\x1b[0m",
 		)
 
-	case BlessDisabled:
+	case r.BlessStrategy == BlessDisabled:
 		t.Fail()
 
-	case BlessEnabled:
-		if err := bless(a.Output, got); err != nil {
+	case r.BlessStrategy == BlessEnabled:
+		if err := r.bless(expected, got); err != nil {
 			t.Log("unable to bless output:", err)
 			t.Fail()
-			return
+			return got
 		}
 
 		messages = append(
@@ -136,19 +437,25 @@ func (r *Runner[T]) assert(t T, a test.Assertion) {
 
 	logSection(
 		t,
-		"OUTPUT DIFF",
+		title+" DIFF",
 		diff,
 		"",
 		messages...,
 	)
 
+	return got
 }
 
-func location(c test.Content) string {
-	if c.IsEntireFile() {
-		return c.File
+// normalize returns data with trailing newlines normalized, if enabled.
+func (r *Runner[T]) normalize(data []byte) []byte {
+	if r.TrimSpace {
+		return append(bytes.TrimRight(data, "\n"), '\n')
 	}
-	return fmt.Sprintf("%s:%d", c.File, c.Line)
+	return data
+}
+
+func location(c test.Content) string {
+	return c.Location().String()
 }
 
 func log(t LoggerT, fn func(w *strings.Builder)) {
@@ -128,3 +128,221 @@ func prettyPrint(
 	enc.SetIndent("", "  ")
 	return enc.Encode(v)
 }
+
+func TestRunner_idempotent(t *testing.T) {
+	loader := fileloader.NewLoader()
+
+	// expected to pass
+	{
+		tst, err := loader.Load("testdata/pass")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		runner := &Runner[*testing.T]{
+			GenerateOutput: func(
+				_ *testing.T,
+				in runner.Input,
+				out runner.Output,
+			) error {
+				return prettyPrint(in, out)
+			},
+			BlessStrategy: BlessDisabled,
+			Idempotent:    true,
+		}
+
+		runner.Run(t, tst)
+	}
+
+	// expected to fail
+	{
+		tst, err := loader.Load("testdata/not-idempotent")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		runner := &Runner[*testingT]{
+			GenerateOutput: func(
+				_ *testingT,
+				in runner.Input,
+				out runner.Output,
+			) error {
+				var v any
+				if err := json.NewDecoder(in).Decode(&v); err != nil {
+					return err
+				}
+
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode([]any{v})
+			},
+			BlessStrategy: BlessDisabled,
+			Idempotent:    true,
+		}
+
+		x := &testingT{T: t}
+		runner.Run(x, tst)
+
+		for _, leaf := range x.leaves() {
+			if !leaf.Failed() {
+				x.Errorf("expected %q to fail", leaf.Name())
+			}
+		}
+	}
+}
+
+func TestRunner_roundTrip(t *testing.T) {
+	loader := fileloader.NewLoader()
+
+	// expected to pass
+	{
+		tst, err := loader.Load("testdata/roundtrip")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		runner := &Runner[*testing.T]{
+			GenerateOutput: func(
+				_ *testing.T,
+				in runner.Input,
+				out runner.Output,
+			) error {
+				out.(RoundTripOutput[*testing.T]).SetRoundTrip(
+					func(
+						_ *testing.T,
+						in runner.Input,
+						out runner.Output,
+					) error {
+						return prettyPrint(in, out)
+					},
+				)
+				return prettyPrint(in, out)
+			},
+			BlessStrategy: BlessDisabled,
+		}
+
+		runner.Run(t, tst)
+	}
+
+	// expected to fail, the round-trip content is not checked
+	{
+		tst, err := loader.Load("testdata/roundtrip")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		runner := &Runner[*testingT]{
+			GenerateOutput: func(
+				_ *testingT,
+				in runner.Input,
+				out runner.Output,
+			) error {
+				return prettyPrint(in, out)
+			},
+			BlessStrategy: BlessDisabled,
+		}
+
+		x := &testingT{T: t}
+		runner.Run(x, tst)
+
+		for _, leaf := range x.leaves() {
+			if !leaf.Failed() {
+				x.Errorf("expected %q to fail", leaf.Name())
+			}
+		}
+	}
+}
+
+func TestRunner_repeat(t *testing.T) {
+	loader := fileloader.NewLoader()
+
+	// expected to pass
+	{
+		tst, err := loader.Load("testdata/pass")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		runner := &Runner[*testing.T]{
+			GenerateOutput: func(
+				_ *testing.T,
+				in runner.Input,
+				out runner.Output,
+			) error {
+				return prettyPrint(in, out)
+			},
+			BlessStrategy:  BlessDisabled,
+			Repeat:         5,
+			VaryGOMAXPROCS: true,
+		}
+
+		runner.Run(t, tst)
+	}
+
+	// expected to fail
+	{
+		tst, err := loader.Load("testdata/pass")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		calls := 0
+		runner := &Runner[*testingT]{
+			GenerateOutput: func(
+				_ *testingT,
+				in runner.Input,
+				out runner.Output,
+			) error {
+				calls++
+				if calls%3 == 0 {
+					_, err := io.WriteString(out, "<nondeterministic>\n")
+					return err
+				}
+				return prettyPrint(in, out)
+			},
+			BlessStrategy: BlessDisabled,
+			Repeat:        3,
+		}
+
+		x := &testingT{T: t}
+		runner.Run(x, tst)
+
+		for _, leaf := range x.leaves() {
+			if !leaf.Failed() && !leaf.Skipped() {
+				x.Errorf("expected %q to fail", leaf.Name())
+			}
+		}
+	}
+
+	// expected to fail, GOMAXPROCS can not be varied in parallel tests
+	t.Run("parallel", func(t *testing.T) {
+		t.Parallel()
+
+		tst, err := loader.Load("testdata/pass")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		runner := &Runner[*testingT]{
+			GenerateOutput: func(
+				_ *testingT,
+				in runner.Input,
+				out runner.Output,
+			) error {
+				return prettyPrint(in, out)
+			},
+			BlessStrategy:  BlessDisabled,
+			Repeat:         2,
+			VaryGOMAXPROCS: true,
+		}
+
+		x := &testingT{T: t}
+		runner.Run(x, tst)
+
+		for _, leaf := range x.leaves() {
+			if !leaf.Failed() && !leaf.Skipped() {
+				x.Errorf("expected %q to fail", leaf.Name())
+			}
+		}
+	})
+}
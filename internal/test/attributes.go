@@ -0,0 +1,148 @@
+package test
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Attributes is a set of key-value pairs that provide additional
+// loader-specific information about test content.
+type Attributes map[string]string
+
+// Has returns true if the attribute with the given key is present.
+func (a Attributes) Has(key string) bool {
+	_, ok := a[key]
+	return ok
+}
+
+// String returns the value of the attribute with the given key, or def if the
+// attribute is not present.
+func (a Attributes) String(key, def string) string {
+	if v, ok := a[key]; ok {
+		return v
+	}
+	return def
+}
+
+// Int returns the value of the attribute with the given key as an integer, or
+// def if the attribute is not present.
+func (a Attributes) Int(key string, def int) (int, error) {
+	v, ok := a[key]
+	if !ok {
+		return def, nil
+	}
+
+	n, err := ParseIntAttribute(v)
+	if err != nil {
+		return 0, fmt.Errorf("%q attribute: %w", key, err)
+	}
+
+	return n, nil
+}
+
+// Bool returns the value of the attribute with the given key as a boolean, or
+// def if the attribute is not present.
+//
+// An attribute that is present but has an empty value, such as a flag, is
+// treated as true.
+func (a Attributes) Bool(key string, def bool) (bool, error) {
+	v, ok := a[key]
+	if !ok {
+		return def, nil
+	}
+
+	b, err := ParseBoolAttribute(v)
+	if err != nil {
+		return false, fmt.Errorf("%q attribute: %w", key, err)
+	}
+
+	return b, nil
+}
+
+// Duration returns the value of the attribute with the given key as a
+// [time.Duration], or def if the attribute is not present.
+func (a Attributes) Duration(key string, def time.Duration) (time.Duration, error) {
+	v, ok := a[key]
+	if !ok {
+		return def, nil
+	}
+
+	d, err := ParseDurationAttribute(v)
+	if err != nil {
+		return 0, fmt.Errorf("%q attribute: %w", key, err)
+	}
+
+	return d, nil
+}
+
+// Enum returns the value of the attribute with the given key, or def if the
+// attribute is not present. It returns an error if the value is not one of the
+// allowed values.
+func (a Attributes) Enum(key, def string, allowed ...string) (string, error) {
+	v, ok := a[key]
+	if !ok {
+		return def, nil
+	}
+
+	if err := ParseEnumAttribute(v, allowed); err != nil {
+		return "", fmt.Errorf("%q attribute: %w", key, err)
+	}
+
+	return v, nil
+}
+
+// ParseIntAttribute parses an integer attribute value.
+func ParseIntAttribute(v string) (int, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an integer", v)
+	}
+	return n, nil
+}
+
+// ParseBoolAttribute parses a boolean attribute value. An empty value is true.
+func ParseBoolAttribute(v string) (bool, error) {
+	if v == "" {
+		return true, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%q is not a boolean", v)
+	}
+
+	return b, nil
+}
+
+// ParseDurationAttribute parses a duration attribute value, such as "1m30s".
+func ParseDurationAttribute(v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a duration", v)
+	}
+	return d, nil
+}
+
+// ParseEnumAttribute returns an error if v is not one of the allowed values.
+func ParseEnumAttribute(v string, allowed []string) error {
+	if slices.Contains(allowed, v) {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%q is not one of %s",
+		v,
+		quoteList(allowed),
+	)
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return strings.Join(quoted, ", ")
+}
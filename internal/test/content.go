@@ -1,5 +1,7 @@
 package test
 
+import "fmt"
+
 // Content is data used as input or output in tests.
 type Content struct {
 	// ContentMetaData is additional information about the content.
@@ -24,16 +26,71 @@ type ContentMetaData struct {
 	// If the range is [0, 0), the content represents the entire file.
 	Begin, End int64
 
+	// Name is an optional name that identifies the content.
+	//
+	// Named inputs are combined into a single assertion, rather than forming a
+	// matrix, such as the "old" and "new" inputs of a migration. Named outputs
+	// distinguish between several outputs produced from the same input, such
+	// as "stdout" and "stderr". Fixtures may also be named.
+	Name string
+
+	// Stage is the name of the pipeline stage that produces the content, if
+	// any. Outputs with stages are chained together, such that the actual
+	// output of each stage is the input to the next.
+	Stage string
+
+	// Group is the name of the group to which the content belongs, or an empty
+	// string if the content does not belong to a named group.
+	Group string
+
+	// Caption is an optional disambiguating name, title or short description of
+	// the content.
+	Caption string
+
+	// Headings is the list of document headings under which the content
+	// appears, outermost first, if the loader supports headings.
+	Headings []string
+
 	// Language is the language of the content, if known, e.g. "json", "yaml",
 	// etc. Content with an empty language is treated as plain text.
 	Language string
 
 	// Attributes is a set of key-value pairs that provide additional
 	// loader-specific information about the data.
-	Attributes map[string]string
+	Attributes Attributes
+
+	// Encode is an optional function that converts data into the form in which
+	// it is stored within the file, such as when the content is embedded within
+	// a larger document that requires escaping. It is used when blessing test
+	// output. If it is nil the data is written to the file verbatim.
+	Encode func([]byte) []byte
 }
 
 // IsEntireFile returns true if the content occupies the entire file.
 func (m ContentMetaData) IsEntireFile() bool {
 	return m.Begin == 0 && m.End == 0
 }
+
+// Location returns the location of the content within its file.
+func (m ContentMetaData) Location() Location {
+	return Location{m.File, m.Line}
+}
+
+// Location describes where content was loaded from.
+type Location struct {
+	// File is the path of the file from which the content was loaded.
+	File string
+
+	// Line is the line number within the file where the content begins, or 0 if
+	// the content represents the entire file.
+	Line int
+}
+
+// String returns a "file:line" representation of the location, or just the
+// file name if the content represents the entire file.
+func (l Location) String() string {
+	if l.Line == 0 {
+		return l.File
+	}
+	return fmt.Sprintf("%s:%d", l.File, l.Line)
+}
@@ -80,9 +80,11 @@ func mergeNaive(tests []Test) Test {
 	}
 
 	return Test{
-		Name:       tests[0].Name,
-		Skip:       tests[0].Skip,
-		SubTests:   Merge(subTests...),
-		Assertions: assertions,
+		Name:        tests[0].Name,
+		Description: tests[0].Description,
+		Skip:        tests[0].Skip,
+		Pipeline:    tests[0].Pipeline,
+		SubTests:    Merge(subTests...),
+		Assertions:  assertions,
 	}
 }
@@ -2,10 +2,16 @@ package test
 
 // Test is a (possibly nested) test.
 type Test struct {
-	Name       string
-	Skip       bool
-	SubTests   []Test
-	Assertions []Assertion
+	Name        string
+	Description string
+	Skip        bool
+	SubTests    []Test
+	Assertions  []Assertion
+
+	// Pipeline indicates that each sub-test is a stage of a pipeline. The
+	// stages are run in order, with the actual output of each stage used as
+	// the input to the next.
+	Pipeline bool
 }
 
 // IsEmpty returns true if the test has no sub-tests or assertions.
@@ -17,6 +23,18 @@ func (t Test) IsEmpty() bool {
 type Assertion struct {
 	Input  Content
 	Output Content
+
+	// NamedInputs is a list of named inputs that are used together with Input,
+	// which may itself be one of the named inputs.
+	NamedInputs []Content
+
+	// RoundTrip is the expected result of converting the actual output back
+	// into an input, if it differs from Input.
+	RoundTrip *Content
+
+	// Fixtures is a list of additional content that provides context for the
+	// input, such as a schema or configuration file.
+	Fixtures []Content
 }
 
 // New creates a new [Test].
@@ -45,6 +63,21 @@ func WithSkip(skip bool) Option {
 	}
 }
 
+// WithDescription is a [TestOption] that sets a human-readable description of
+// the test.
+func WithDescription(desc string) Option {
+	return func(t *Test) {
+		t.Description = desc
+	}
+}
+
+// WithPipeline is a [TestOption] that sets the pipeline flag.
+func WithPipeline(pipeline bool) Option {
+	return func(t *Test) {
+		t.Pipeline = pipeline
+	}
+}
+
 // WithSubTests is a [TestOption] that adds sub-tests to the test.
 func WithSubTests(subTests ...Test) Option {
 	return func(t *Test) {
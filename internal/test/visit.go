@@ -0,0 +1,64 @@
+package test
+
+import "errors"
+
+// Visitor is an interface for visiting the tests and assertions within a test
+// hierarchy.
+type Visitor interface {
+	// VisitTest is called for each test.
+	//
+	// path is the names of the test's ancestors, followed by the name of the
+	// test itself. If it returns [SkipTest], the test's sub-tests and
+	// assertions are not visited.
+	VisitTest(path []string, t Test) error
+
+	// VisitAssertion is called for each assertion.
+	//
+	// path is the path of the test that contains the assertion.
+	VisitAssertion(path []string, a Assertion) error
+}
+
+// SkipTest is a special error that may be returned by [Visitor.VisitTest] to
+// skip the test's sub-tests and assertions.
+var SkipTest = errors.New("skip this test")
+
+// Walk visits each of the given tests and their descendants, in depth-first
+// order. Sub-tests are visited before the assertions within the same test.
+//
+// It stops at the first error returned by v, other than [SkipTest].
+func Walk(tests []Test, v Visitor, options ...VisitOption) error {
+	cfg := newVisitConfig(options)
+	if cfg.TestingT != nil {
+		cfg.TestingT.Helper()
+	}
+
+	return walk(cfg, nil, tests, v)
+}
+
+func walk(cfg visitConfig, parent []string, tests []Test, v Visitor) error {
+	if cfg.TestingT != nil {
+		cfg.TestingT.Helper()
+	}
+
+	for _, t := range tests {
+		path := append(parent[:len(parent):len(parent)], t.Name)
+
+		if err := v.VisitTest(path, t); err == SkipTest {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if err := walk(cfg, path, t.SubTests, v); err != nil {
+			return err
+		}
+
+		for _, a := range t.Assertions {
+			if err := v.VisitAssertion(path, a); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,260 @@
+package aureus
+
+import (
+	"io/fs"
+
+	"github.com/yuin/goldmark"
+
+	"github.com/dogmatiq/aureus/internal/loader"
+	"github.com/dogmatiq/aureus/internal/loader/fileloader"
+	"github.com/dogmatiq/aureus/internal/loader/markdownloader"
+	"github.com/dogmatiq/aureus/internal/loader/txtarloader"
+	"github.com/dogmatiq/aureus/internal/test"
+)
+
+// Loader is an interface for loading tests from a directory.
+//
+// Use the [WithLoaders] option to load tests from sources other than those
+// supported by Aureus itself.
+type Loader interface {
+	// Load returns a test built from the files in the directory at dir within
+	// fsys.
+	//
+	// The returned test should be named after the directory, such that it is
+	// merged with the tests produced by other loaders. [LoadDir] may be used to
+	// build such a test. Empty tests are ignored.
+	Load(fsys fs.FS, dir string) (Test, error)
+}
+
+// LoaderFunc is an adapter that allows the use of an ordinary function as a
+// [Loader].
+type LoaderFunc func(fsys fs.FS, dir string) (Test, error)
+
+// Load returns fn(fsys, dir).
+func (fn LoaderFunc) Load(fsys fs.FS, dir string) (Test, error) {
+	return fn(fsys, dir)
+}
+
+// Test is a (possibly nested) test.
+type Test = test.Test
+
+// Assertion represents a requirement that an input match a specific output.
+type Assertion = test.Assertion
+
+// TestBuilder builds a [Test] from groups of correlated inputs and outputs.
+//
+// Inputs and outputs in the same group form a matrix of test cases. The zero
+// value is ready to use.
+type TestBuilder = loader.TestBuilder
+
+// Content is an input or output loaded by a [Loader], along with information
+// about the role it plays within a test.
+type Content = loader.Content
+
+// ContentEnvelope is a container for [Content] and information about where it
+// was loaded from.
+type ContentEnvelope = loader.ContentEnvelope
+
+// ContentRole is an enumeration of the roles that [Content] can play within a
+// test.
+type ContentRole = loader.ContentRole
+
+const (
+	// NoRole indicates that the content has no specific role within the test.
+	// Such content is ignored by the [TestBuilder].
+	NoRole = loader.NoRole
+
+	// InputRole indicates that the content is an input to a test.
+	InputRole = loader.Input
+
+	// OutputRole indicates that the content is the expected output from a
+	// test.
+	OutputRole = loader.Output
+
+	// FixtureRole indicates that the content is additional context for the
+	// inputs in its group, or for all of the inputs built by the same
+	// [TestBuilder] if it does not belong to a named group.
+	FixtureRole = loader.Fixture
+)
+
+// Group is the group to which [Content] belongs. Inputs and outputs in the same
+// group form a matrix of test cases.
+type Group = loader.Group
+
+// NamedGroup returns a [Group] with the given name.
+func NamedGroup(name string) *Group {
+	return loader.NamedGroup(name)
+}
+
+// UnnamedGroup returns a [Group] with no name.
+func UnnamedGroup() *Group {
+	return loader.UnnamedGroup()
+}
+
+// LoadDir returns a test named after the directory at dir within fsys.
+//
+// build is called for each (non-hidden) file in the directory. It adds the
+// file's content to the [TestBuilder]. If recurse is true, sub-directories
+// produce nested tests. Any directory that begins with an underscore produces
+// a test that is marked as skipped.
+func LoadDir(
+	fsys fs.FS,
+	dir string,
+	recurse bool,
+	build func(b *TestBuilder, fsys fs.FS, filePath string) error,
+) (Test, error) {
+	return loader.LoadDir(fsys, dir, recurse, build)
+}
+
+// WithLoaders is a [RunOption] that loads tests using additional loaders, as
+// well as the loaders that are built in to Aureus.
+//
+// The tests produced by each loader are merged into a single test hierarchy,
+// such that tests with the same name are combined.
+func WithLoaders(loaders ...Loader) RunOption {
+	return func(o *runOptions) {
+		o.Loaders = append(o.Loaders, loaders...)
+	}
+}
+
+// FileContentLoader is a function that identifies flat-file tests and loads
+// their content.
+//
+// name is the name of the file, after any special characters such as a leading
+// underscore have been removed. The f.Stat() method can be used to get the
+// actual file name.
+//
+// If the returned content's role is [NoRole], the file is ignored.
+type FileContentLoader = fileloader.ContentLoader
+
+// LoadFileContent is the default [FileContentLoader]. It implements the
+// "<group>.input" and "<group>.output" naming conventions.
+func LoadFileContent(name string, f fs.File) (Content, error) {
+	return fileloader.LoadContent(name, f)
+}
+
+// LoadGoldenFileContent is a [FileContentLoader] that implements the
+// "<name>.golden" naming convention. See [GoldenFiles].
+func LoadGoldenFileContent(name string, f fs.File) (Content, error) {
+	return fileloader.LoadGoldenContent(name, f)
+}
+
+// ParseFileName returns the (data-less) content described by a file name that
+// follows the "<group>.input" and "<group>.output" naming conventions used by
+// [LoadFileContent]. It may be used to implement a [FileContentLoader] that
+// extends these conventions.
+func ParseFileName(name string) Content {
+	return loader.ParseFileName(name)
+}
+
+// WithFileContentLoader is a [RunOption] that configures an alternative
+// [FileContentLoader] used to identify flat-file tests and load their content.
+//
+// This allows the use of file naming conventions other than those supported by
+// Aureus itself.
+func WithFileContentLoader(load FileContentLoader) RunOption {
+	return func(o *runOptions) {
+		o.LoadFileContent = load
+	}
+}
+
+// MarkdownContentLoader is a function that returns content obtained from a
+// fenced code block within a Markdown document.
+//
+// headings is a list of headings that precede the code block in the document,
+// which may be empty. The last element of the list is the heading that the code
+// block is "within".
+//
+// info is the "info string" of the code block, that is, the line of text that
+// contains the language and other loader-specific information.
+//
+// If the returned content's role is [NoRole], the code block is ignored.
+type MarkdownContentLoader = markdownloader.ContentLoader
+
+// LoadMarkdownContent is the default [MarkdownContentLoader]. It identifies
+// inputs and outputs using attributes such as "au:input" and "au:output".
+func LoadMarkdownContent(
+	headings []string,
+	info, code string,
+) (_ Content, skip bool, _ error) {
+	return markdownloader.LoadContent(headings, info, code)
+}
+
+// MarkdownAttributePrefix is a [RunOption] that identifies tests within
+// Markdown documents using attributes with the given prefix instead of "au:",
+// such as "test:input", "test:table" and "test:session".
+//
+// The prefix applies to code blocks unless an alternative
+// [MarkdownContentLoader] is configured using [WithMarkdownContentLoader], in
+// which case it only applies to tables and shell sessions.
+func MarkdownAttributePrefix(prefix string) RunOption {
+	return func(o *runOptions) {
+		o.MarkdownAttributePrefix = prefix
+	}
+}
+
+// WithMarkdownContentLoader is a [RunOption] that configures an alternative
+// [MarkdownContentLoader] used to load content from fenced code blocks within
+// Markdown documents.
+func WithMarkdownContentLoader(load MarkdownContentLoader) RunOption {
+	return func(o *runOptions) {
+		o.LoadMarkdownContent = load
+	}
+}
+
+// WithMarkdownExtensions is a [RunOption] that enables extensions to the
+// Markdown parser used to load tests from Markdown documents.
+//
+// This allows tests to be loaded from documents that use Markdown dialects
+// other than CommonMark. GitHub Flavored Markdown tables are always enabled.
+func WithMarkdownExtensions(extensions ...goldmark.Extender) RunOption {
+	return func(o *runOptions) {
+		o.MarkdownExtensions = append(o.MarkdownExtensions, extensions...)
+	}
+}
+
+// builtInLoaders returns the loaders that are built in to Aureus, configured
+// according to opts.
+func builtInLoaders(opts runOptions) []Loader {
+	return []Loader{
+		LoaderFunc(func(fsys fs.FS, dir string) (Test, error) {
+			return fileloader.
+				NewLoader(
+					fileloader.WithFS(fsys),
+					fileloader.WithRecursion(opts.Recursive),
+					fileloader.WithFileLoader(opts.LoadFileContent),
+					fileloader.WithLayout(opts.Layout),
+				).
+				Load(dir)
+		}),
+		LoaderFunc(func(fsys fs.FS, dir string) (Test, error) {
+			options := []markdownloader.LoadOption{
+				markdownloader.WithFS(fsys),
+				markdownloader.WithRecursion(opts.Recursive),
+				markdownloader.WithContentLoader(opts.LoadMarkdownContent),
+				markdownloader.WithParser(
+					markdownloader.NewParser(opts.MarkdownExtensions...),
+				),
+			}
+
+			if opts.MarkdownAttributePrefix != "" {
+				options = append(
+					options,
+					markdownloader.WithAttributePrefix(opts.MarkdownAttributePrefix),
+				)
+			}
+
+			return markdownloader.
+				NewLoader(options...).
+				Load(dir)
+		}),
+		LoaderFunc(func(fsys fs.FS, dir string) (Test, error) {
+			return txtarloader.
+				NewLoader(
+					txtarloader.WithFS(fsys),
+					txtarloader.WithRecursion(opts.Recursive),
+				).
+				Load(dir)
+		}),
+	}
+}
@@ -0,0 +1,144 @@
+package aureus
+
+import (
+	"fmt"
+)
+
+// Mux is an [OutputGenerator] that dispatches to other generators based on the
+// languages and attributes of each test's input and expected output.
+//
+// Use [NewMux] to construct a new Mux, although the zero value is also ready to
+// use. Handlers are registered using the Handle* methods, after which
+// [Mux.Generate] may be passed to [Run].
+//
+// Handlers registered with [Mux.HandleAttr] take precedence over those
+// registered with [Mux.HandleLanguages]. Otherwise, handlers are tried in the
+// order that they were registered.
+type Mux[T TestingT[T]] struct {
+	attrs []muxHandler[T]
+	langs []muxHandler[T]
+	def   *muxHandler[T]
+	skip  bool
+}
+
+// NewMux returns a new [Mux] with no handlers.
+func NewMux[T TestingT[T]]() *Mux[T] {
+	return &Mux[T]{}
+}
+
+type muxHandler[T TestingT[T]] struct {
+	Name    string
+	Matches func(Input, Output) bool
+	Gen     OutputGenerator[T]
+}
+
+// HandleLanguages registers a generator for tests with the given input and
+// output languages.
+func (m *Mux[T]) HandleLanguages(in, out string, gen OutputGenerator[T]) {
+	m.langs = append(
+		m.langs,
+		muxHandler[T]{
+			fmt.Sprintf("%s -> %s", displayLanguage(in), displayLanguage(out)),
+			func(i Input, o Output) bool {
+				return i.Language() == in && o.Language() == out
+			},
+			gen,
+		},
+	)
+}
+
+// HandleAttr registers a generator for tests where either the input or the
+// expected output has an attribute with the given key and value.
+func (m *Mux[T]) HandleAttr(key, value string, gen OutputGenerator[T]) {
+	m.attrs = append(
+		m.attrs,
+		muxHandler[T]{
+			fmt.Sprintf("%s=%s", key, value),
+			func(i Input, o Output) bool {
+				if v, ok := i.Attributes()[key]; ok && v == value {
+					return true
+				}
+				v, ok := o.Attributes()[key]
+				return ok && v == value
+			},
+			gen,
+		},
+	)
+}
+
+// HandleDefault registers a generator for tests that are not matched by any
+// other handler.
+func (m *Mux[T]) HandleDefault(gen OutputGenerator[T]) {
+	m.def = &muxHandler[T]{
+		"default",
+		func(Input, Output) bool { return true },
+		gen,
+	}
+}
+
+// SkipUnhandled sets whether tests that are not matched by any handler are
+// skipped. By default such tests fail.
+func (m *Mux[T]) SkipUnhandled(skip bool) {
+	m.skip = skip
+}
+
+// Generate is an [OutputGenerator] that produces output using the handler that
+// matches the test's input and expected output. The name of the handler is
+// logged, so that it is shown alongside any differences in the output, and
+// errors returned by the handler are prefixed with its name.
+func (m *Mux[T]) Generate(t T, in Input, out Output) error {
+	t.Helper()
+
+	h, ok := m.handler(in, out)
+	if !ok {
+		if m.skip {
+			t.Log("skipping test: no handler for", describeMuxTest(in, out))
+			t.SkipNow()
+			return nil
+		}
+
+		return fmt.Errorf("no handler for %s", describeMuxTest(in, out))
+	}
+
+	t.Log("using the", h.Name, "handler")
+
+	if err := h.Gen(t, in, out); err != nil {
+		return fmt.Errorf("%s handler: %w", h.Name, err)
+	}
+
+	return nil
+}
+
+func (m *Mux[T]) handler(in Input, out Output) (muxHandler[T], bool) {
+	for _, handlers := range [][]muxHandler[T]{m.attrs, m.langs} {
+		for _, h := range handlers {
+			if h.Matches(in, out) {
+				return h, true
+			}
+		}
+	}
+
+	if m.def != nil {
+		return *m.def, true
+	}
+
+	return muxHandler[T]{}, false
+}
+
+// describeMuxTest returns a description of a test's languages, for use in
+// messages about unhandled tests.
+func describeMuxTest(in Input, out Output) string {
+	return fmt.Sprintf(
+		"%s -> %s",
+		displayLanguage(in.Language()),
+		displayLanguage(out.Language()),
+	)
+}
+
+// displayLanguage returns a human-readable representation of a language.
+func displayLanguage(lang string) string {
+	if lang == "" {
+		return "(plain text)"
+	}
+	return lang
+}
@@ -1,15 +1,17 @@
 package aureus
 
 import (
+	"io/fs"
 	"path"
 	"runtime"
 	"strings"
 
 	"github.com/dogmatiq/aureus/internal/cliflags"
 	"github.com/dogmatiq/aureus/internal/loader/fileloader"
-	"github.com/dogmatiq/aureus/internal/loader/markdownloader"
+	"github.com/dogmatiq/aureus/internal/rootfs"
 	"github.com/dogmatiq/aureus/internal/runner"
 	"github.com/dogmatiq/aureus/internal/test"
+	"github.com/yuin/goldmark"
 )
 
 // TestingT is a constraint for the subset of [testing.T] that is used by Aureus
@@ -37,58 +39,43 @@ func Run[T runner.TestingT[T]](
 ) {
 	t.Helper()
 
-	opts := runOptions{
-		Dir:           "./testdata",
-		Recursive:     true,
-		TrimSpace:     true,
-		BlessStrategy: runner.BlessAvailable,
-	}
-
-	flags := cliflags.Get()
-	if flags.Bless {
-		Bless(true)(&opts)
-	}
+	opts := newRunOptions(options)
 
-	if flags.Lang != "" {
-		pred := func(a test.Assertion) bool {
-			return a.Input.Language == flags.Lang ||
-				a.Output.Language == flags.Lang
-		}
-		AssertionFilter(pred)(&opts)
-	}
-
-	for _, opt := range options {
-		opt(&opts)
-	}
-
-	fileLoader := fileloader.NewLoader(fileloader.WithRecursion(opts.Recursive))
-	fileTests, err := fileLoader.Load(opts.Dir)
+	tests, err := loadTests(opts)
 	if err != nil {
 		t.Log("failed to load tests:", err)
 		t.Fail()
 		return
 	}
 
-	markdownLoader := markdownloader.NewLoader(markdownloader.WithRecursion(opts.Recursive))
-	markdownTests, err := markdownLoader.Load(opts.Dir)
-	if err != nil {
-		t.Log("failed to load tests:", err)
-		t.Fail()
-		return
+	writeFile := opts.WriteFile
+	if w, ok := opts.FS.(writableFS); ok && writeFile == nil {
+		writeFile = w.WriteFile
+	}
+
+	blessStrategy := opts.BlessStrategy
+	if writeFile == nil {
+		if blessStrategy == runner.BlessEnabled {
+			t.Log("blessing is disabled because the file system is read-only")
+		}
+		blessStrategy = runner.BlessDisabled
 	}
 
 	r := runner.Runner[T]{
 		GenerateOutput: func(t T, in runner.Input, out runner.Output) error {
-			return g(t, in, out)
+			return g(t, input{in}, out)
 		},
 		TrimSpace:       opts.TrimSpace,
-		BlessStrategy:   opts.BlessStrategy,
+		BlessStrategy:   blessStrategy,
 		AssertionFilter: opts.AssertionFilter,
 		PackagePath:     guessPackagePath(),
+		FS:              opts.FS,
+		WriteFile:       writeFile,
+		Idempotent:      opts.Idempotent,
+		Repeat:          opts.Repeat,
+		VaryGOMAXPROCS:  opts.VaryGOMAXPROCS,
 	}
 
-	tests := test.Merge(fileTests, markdownTests)
-
 	if len(tests) == 0 {
 		t.Log("no tests found")
 	} else {
@@ -98,15 +85,97 @@ func Run[T runner.TestingT[T]](
 	}
 }
 
-// RunOption is an option that changes the behavior of [Run].
+// RunOption is an option that changes the behavior of [Run] and [Load].
 type RunOption func(*runOptions)
 
+// newRunOptions returns the options to use for the given [RunOption] values,
+// including those implied by command-line flags.
+func newRunOptions(options []RunOption) runOptions {
+	opts := runOptions{
+		FS:              rootfs.FS,
+		Dir:             "./testdata",
+		Recursive:       true,
+		LoadFileContent: LoadFileContent,
+		TrimSpace:       true,
+		BlessStrategy:   runner.BlessAvailable,
+	}
+
+	flags := cliflags.Get()
+	if flags.Bless {
+		Bless(true)(&opts)
+	}
+
+	if flags.Repeat > 0 {
+		Repeat(flags.Repeat)(&opts)
+	}
+
+	if flags.Lang != "" {
+		pred := func(a Assertion) bool {
+			return a.Input.Language == flags.Lang ||
+				a.Output.Language == flags.Lang
+		}
+		AssertionFilter(pred)(&opts)
+	}
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	return opts
+}
+
+// loadTests loads tests using all of the loaders configured by opts, and
+// merges them into a single hierarchy.
+func loadTests(opts runOptions) ([]test.Test, error) {
+	var tests []test.Test
+
+	for _, l := range append(builtInLoaders(opts), opts.Loaders...) {
+		x, err := l.Load(opts.FS, opts.Dir)
+		if err != nil {
+			return nil, err
+		}
+
+		if !x.IsEmpty() {
+			tests = append(tests, x)
+		}
+	}
+
+	tests = test.Merge(tests...)
+
+	if opts.AttributeSchema != nil {
+		v := schemaValidator(opts.AttributeSchema)
+		if err := test.Walk(tests, v); err != nil {
+			return nil, err
+		}
+	}
+
+	return tests, nil
+}
+
 type runOptions struct {
-	Dir             string
-	Recursive       bool
-	TrimSpace       bool
-	BlessStrategy   runner.BlessStrategy
-	AssertionFilter func(test.Assertion) bool
+	FS                      fs.FS
+	Dir                     string
+	Recursive               bool
+	Layout                  Layout
+	LoadFileContent         FileContentLoader
+	LoadMarkdownContent     MarkdownContentLoader
+	MarkdownAttributePrefix string
+	MarkdownExtensions      []goldmark.Extender
+	Loaders                 []Loader
+	AttributeSchema         map[string]AttributeSpec
+	Idempotent              bool
+	Repeat                  int
+	VaryGOMAXPROCS          bool
+	TrimSpace               bool
+	BlessStrategy           runner.BlessStrategy
+	WriteFile               func(name string, data []byte) error
+	AssertionFilter         func(Assertion) bool
+}
+
+// writableFS is an [fs.FS] that supports replacing the content of files.
+type writableFS interface {
+	fs.FS
+	WriteFile(name string, data []byte) error
 }
 
 // FromDir is a [RunOption] that sets the directory to search for tests. By
@@ -117,6 +186,20 @@ func FromDir(dir string) RunOption {
 	}
 }
 
+// FromFS is a [RunOption] that searches for tests in the directory at dir
+// within fsys, instead of the host file system. This allows tests to be loaded
+// from an [embed.FS], or to be constructed in-memory using a [testing/fstest.MapFS].
+//
+// If fsys has a WriteFile(name string, data []byte) error method it is used to
+// bless test output. Otherwise, blessing is disabled unless the [BlessTo]
+// option is used.
+func FromFS(fsys fs.FS, dir string) RunOption {
+	return func(o *runOptions) {
+		o.FS = fsys
+		o.Dir = dir
+	}
+}
+
 // Recursive is a [RunOption] that enables or disables recursion when searching
 // for test cases. By default recursion is enabled.
 func Recursive(on bool) RunOption {
@@ -125,6 +208,83 @@ func Recursive(on bool) RunOption {
 	}
 }
 
+// GoldenFiles is a [RunOption] that enables or disables the "<name>.golden"
+// naming convention for flat-file tests.
+//
+// When enabled, a file named "<name>.golden" contains the expected output for
+// the input in the sibling file named "<name>", and the language of both is
+// determined by the file extension of "<name>". Files that have no ".golden"
+// sibling are ignored. The "<group>.input" and "<group>.output" conventions are
+// not used.
+//
+// By default the "<name>.golden" convention is disabled. Enabling it is
+// equivalent to using [WithFileContentLoader] with [LoadGoldenFileContent].
+func GoldenFiles(on bool) RunOption {
+	return func(o *runOptions) {
+		if on {
+			o.LoadFileContent = LoadGoldenFileContent
+		} else {
+			o.LoadFileContent = LoadFileContent
+		}
+	}
+}
+
+// Layout is an enumeration of the ways in which flat-file tests may be arranged
+// within directories.
+type Layout = fileloader.Layout
+
+const (
+	// FlatLayout is a [Layout] in which the inputs and outputs of many tests
+	// may be placed within the same directory, and are grouped into tests
+	// according to their file names. It is the default layout.
+	FlatLayout = fileloader.FlatLayout
+
+	// CaseLayout is a [Layout] in which each directory is a single test case
+	// named after the directory, containing files such as "input.json" and
+	// "output.json". If a directory contains multiple inputs or outputs they
+	// form a test matrix.
+	//
+	// An optional "attributes.json" file contains a JSON object of string
+	// attributes that apply to every input and output within the directory,
+	// and an optional "README.md" file contains a description of the test
+	// case.
+	CaseLayout = fileloader.CaseLayout
+
+	// VariantLayout is a [Layout] in which a single set of inputs is tested
+	// against several "variants" of expected outputs.
+	//
+	// Each file in an "inputs" directory is paired with the files that have
+	// the same base name in each "outputs/<variant>" directory. For example,
+	// "inputs/foo.md" is paired with "outputs/html/foo.html". Each variant
+	// produces a separate sub-test, and the name of the variant is available
+	// in the output's "variant" attribute.
+	VariantLayout = fileloader.VariantLayout
+)
+
+// CaseDirectories is a [RunOption] that enables or disables the
+// "directory-per-case" layout for flat-file tests. Enabling it is equivalent to
+// FileLayout(CaseLayout).
+//
+// When enabled, each directory is a single test case named after the directory,
+// containing files such as "input.json" and "output.json". See [CaseLayout].
+func CaseDirectories(on bool) RunOption {
+	return func(o *runOptions) {
+		if on {
+			o.Layout = CaseLayout
+		} else if o.Layout == CaseLayout {
+			o.Layout = FlatLayout
+		}
+	}
+}
+
+// FileLayout is a [RunOption] that sets the [Layout] of flat-file tests within
+// directories. By default the [FlatLayout] is used.
+func FileLayout(l Layout) RunOption {
+	return func(o *runOptions) {
+		o.Layout = l
+	}
+}
+
 // TrimSpace is a [RunOption] that enables or disables trimming of leading and
 // trailing whitespace from test outputs. By default trimming is enabled.
 func TrimSpace(on bool) RunOption {
@@ -150,10 +310,62 @@ func Bless(on bool) RunOption {
 	}
 }
 
+// BlessTo is a [RunOption] that writes blessed output using the given function
+// instead of writing to the file system that the tests were loaded from.
+//
+// name is the path of the file that contains the expected output, and data is
+// the new content of the entire file.
+func BlessTo(write func(name string, data []byte) error) RunOption {
+	return func(o *runOptions) {
+		o.WriteFile = write
+	}
+}
+
+// Idempotent is a [RunOption] that enables or disables idempotence checking.
+//
+// If idempotence checking is enabled, the actual output of each assertion is
+// passed back through the output generator as input, and the test fails if the
+// second pass produces different output. This is useful for testing formatters
+// and other transformations that should be stable when applied repeatedly.
+//
+// By default idempotence checking is disabled.
+func Idempotent(on bool) RunOption {
+	return func(o *runOptions) {
+		o.Idempotent = on
+	}
+}
+
+// Repeat is a [RunOption] that generates the output of each assertion n times,
+// and fails the test if the outputs differ. The differences between the first
+// output and the first repetition that differs from it are shown.
+//
+// This is useful for detecting nondeterminism, such as output that depends on
+// the iteration order of a map. The Go runtime randomizes the iteration order
+// of every map each time it is iterated, so there is no need to vary a hash
+// seed between repetitions. By default the output is generated once, unless
+// the -aureus.repeat flag is set on the command line.
+func Repeat(n int) RunOption {
+	return func(o *runOptions) {
+		o.Repeat = n
+	}
+}
+
+// VaryGOMAXPROCS is a [RunOption] that enables or disables varying
+// [runtime.GOMAXPROCS] between the repetitions performed by [Repeat], which
+// can expose nondeterminism that depends on scheduling.
+//
+// GOMAXPROCS is a process-wide setting, so the test fails if it is a parallel
+// test, or if T does not have a Setenv() method. By default it is disabled.
+func VaryGOMAXPROCS(on bool) RunOption {
+	return func(o *runOptions) {
+		o.VaryGOMAXPROCS = on
+	}
+}
+
 // AssertionFilter is a [RunOption] limits test execution to those tests that
 // use a language that matches the given predicate function. It does not prevent
 // the tests from being loaded.
-func AssertionFilter(pred func(test.Assertion) bool) RunOption {
+func AssertionFilter(pred func(Assertion) bool) RunOption {
 	return func(o *runOptions) {
 		o.AssertionFilter = pred
 	}
@@ -1,8 +1,20 @@
 package aureus_test
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"slices"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/dogmatiq/aureus"
 )
@@ -31,6 +43,66 @@ func prettyPrint(
 	return nil
 }
 
+// upperCase is an [aureus.OutputGenerator] that converts its input to upper
+// case.
+func upperCase(
+	t *testing.T,
+	in aureus.Input,
+	out aureus.Output,
+) error {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(bytes.ToUpper(data))
+	return err
+}
+
+// markdown returns a document made up of the given lines.
+func markdown(lines ...string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// testFS returns a filesystem that contains the given files within the
+// "tests" directory.
+func testFS(files map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for name, data := range files {
+		fsys[path.Join("tests", name)] = &fstest.MapFile{Data: []byte(data)}
+	}
+	return fsys
+}
+
+// runBlessed runs the tests in the "tests" directory of fsys with blessing
+// enabled, and returns the blessed content of each file, keyed by file name.
+//
+// The blessed content is not written back to fsys.
+func runBlessed(
+	t *testing.T,
+	gen aureus.OutputGenerator[*testing.T],
+	fsys fs.FS,
+	options ...aureus.RunOption,
+) map[string]string {
+	blessed := map[string]string{}
+
+	options = append(
+		[]aureus.RunOption{
+			aureus.FromFS(fsys, "tests"),
+			aureus.Bless(true),
+			aureus.BlessTo(func(name string, data []byte) error {
+				blessed[name] = string(data)
+				return nil
+			}),
+		},
+		options...,
+	)
+
+	aureus.Run(t, gen, options...)
+
+	return blessed
+}
+
 func TestRun_flatFile(t *testing.T) {
 	aureus.Run(t, prettyPrint)
 }
@@ -43,3 +115,1094 @@ func TestRun_readme(t *testing.T) {
 		aureus.Recursive(false),
 	)
 }
+
+func TestRun_withLoaders(t *testing.T) {
+	// pairLoader loads tests from ".pair" files, which contain an input and
+	// its expected output separated by a line containing "----".
+	pairLoader := aureus.LoaderFunc(
+		func(fsys fs.FS, dir string) (aureus.Test, error) {
+			return aureus.LoadDir(
+				fsys,
+				dir,
+				true,
+				func(b *aureus.TestBuilder, fsys fs.FS, filePath string) error {
+					name, ok := strings.CutSuffix(path.Base(filePath), ".pair")
+					if !ok {
+						return nil
+					}
+
+					data, err := fs.ReadFile(fsys, filePath)
+					if err != nil {
+						return err
+					}
+
+					in, out, ok := strings.Cut(string(data), "----\n")
+					if !ok {
+						return fmt.Errorf("%s: missing separator", filePath)
+					}
+
+					for _, c := range []aureus.Content{
+						{Role: aureus.InputRole, Data: []byte(in)},
+						{Role: aureus.OutputRole, Data: []byte(out)},
+					} {
+						c.Group = aureus.NamedGroup(name)
+						c.Language = "json"
+
+						if err := b.AddContent(
+							aureus.ContentEnvelope{
+								File:    filePath,
+								Content: c,
+							},
+						); err != nil {
+							return err
+						}
+					}
+
+					return nil
+				},
+			)
+		},
+	)
+
+	aureus.Run(
+		t,
+		prettyPrint,
+		aureus.WithLoaders(pairLoader),
+	)
+}
+
+func TestRun_markdownAttributePrefix(t *testing.T) {
+	aureus.Run(
+		t,
+		prettyPrint,
+		aureus.FromDir("testdata/custom-prefix"),
+		aureus.MarkdownAttributePrefix("test:"),
+	)
+}
+
+func TestRun_fromFS(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"README.md": markdown(
+			"```json au:input au:group=test",
+			`{"a":1}`,
+			"```",
+			"",
+			"```json au:output au:group=test",
+			"<incorrect>",
+			"```",
+		),
+	})
+
+	blessed := runBlessed(t, prettyPrint, fsys)
+
+	want := markdown(
+		"```json au:input au:group=test",
+		`{"a":1}`,
+		"```",
+		"",
+		"```json au:output au:group=test",
+		"{",
+		`  "a": 1`,
+		"}",
+		"```",
+	)
+
+	if got := blessed["tests/README.md"]; got != want {
+		t.Fatalf("unexpected blessed content:\n%s", got)
+	}
+}
+
+func TestRun_blessSharedOutput(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"README.md": markdown(
+			"```json au:input au:group=shared",
+			`{"a":1}`,
+			"```",
+			"",
+			"```json au:input au:group=shared",
+			`{"a":2}`,
+			"```",
+			"",
+			"```json au:output au:group=shared",
+			"<incorrect>",
+			"```",
+			"",
+			"```json au:input au:group=after",
+			`{"b":3}`,
+			"```",
+			"",
+			"```json au:output au:group=after",
+			"<incorrect>",
+			"```",
+		),
+	})
+
+	blessed := runBlessed(t, prettyPrint, fsys)
+
+	// The shared output is blessed once for each input, and the second bless
+	// replaces the first.
+	want := markdown(
+		"```json au:input au:group=shared",
+		`{"a":1}`,
+		"```",
+		"",
+		"```json au:input au:group=shared",
+		`{"a":2}`,
+		"```",
+		"",
+		"```json au:output au:group=shared",
+		"{",
+		`  "a": 2`,
+		"}",
+		"```",
+		"",
+		"```json au:input au:group=after",
+		`{"b":3}`,
+		"```",
+		"",
+		"```json au:output au:group=after",
+		"{",
+		`  "b": 3`,
+		"}",
+		"```",
+	)
+
+	if got := blessed["tests/README.md"]; got != want {
+		t.Fatalf("unexpected blessed content:\n%s", got)
+	}
+}
+
+func TestRun_blessToMultipleOutputs(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"README.md": markdown(
+			"```json au:input au:group=one",
+			`{"a":1}`,
+			"```",
+			"",
+			"```json au:output au:group=one",
+			"<incorrect>",
+			"```",
+			"",
+			"```json au:input au:group=two",
+			`{"b":2}`,
+			"```",
+			"",
+			"```json au:output au:group=two",
+			"<incorrect>",
+			"```",
+		),
+	})
+
+	// Note that the blessed content is deliberately not written back to fsys.
+	blessed := runBlessed(t, prettyPrint, fsys)
+
+	want := markdown(
+		"```json au:input au:group=one",
+		`{"a":1}`,
+		"```",
+		"",
+		"```json au:output au:group=one",
+		"{",
+		`  "a": 1`,
+		"}",
+		"```",
+		"",
+		"```json au:input au:group=two",
+		`{"b":2}`,
+		"```",
+		"",
+		"```json au:output au:group=two",
+		"{",
+		`  "b": 2`,
+		"}",
+		"```",
+	)
+
+	if got := blessed["tests/README.md"]; got != want {
+		t.Fatalf("unexpected blessed content:\n%s", got)
+	}
+}
+
+func TestRun_caseDirectories(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"simple/input.json":  `{"a":1}`,
+		"simple/output.json": "{\n  \"a\": 1\n}\n",
+	})
+
+	suite, err := aureus.Load(
+		aureus.FromFS(fsys, "tests"),
+		aureus.CaseDirectories(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, x := range suite.Tests() {
+		names = append(names, x.Name)
+		for _, s := range x.SubTests {
+			names = append(names, s.Name)
+		}
+	}
+
+	aureus.Run(
+		t,
+		prettyPrint,
+		aureus.FromFS(fsys, "tests"),
+		aureus.CaseDirectories(true),
+	)
+
+	if got, want := strings.Join(names, ","), "tests,simple"; got != want {
+		t.Fatalf("unexpected tests: got %q, want %q", got, want)
+	}
+}
+
+func TestRun_blessEmptyTableCell(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"README.md": markdown(
+			"<!-- au:table text -->",
+			"",
+			"| input | output |",
+			"| ----- | ------ |",
+			"| hello ||",
+			"| world |        |",
+		),
+	})
+
+	blessed := runBlessed(t, upperCase, fsys)
+
+	want := markdown(
+		"<!-- au:table text -->",
+		"",
+		"| input | output |",
+		"| ----- | ------ |",
+		"| hello | HELLO |",
+		"| world | WORLD |",
+	)
+
+	if got := blessed["tests/README.md"]; got != want {
+		t.Fatalf("unexpected blessed content:\n%s", got)
+	}
+}
+
+func TestRun_blessTableCellRoundTrip(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"README.md": markdown(
+			"<!-- au:table text -->",
+			"",
+			"| input       | output |",
+			"| ----------- | ------ |",
+			"| a<br>b      |        |",
+			"| &amp;lt;br> |        |",
+		),
+	})
+
+	// The generator places the input between HTML line breaks, such that the
+	// output contains both literal "<br>" text and newlines.
+	gen := func(t *testing.T, in aureus.Input, out aureus.Output) error {
+		data, err := io.ReadAll(in)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "<br>%s<br>\n", data)
+		return err
+	}
+
+	blessed := runBlessed(t, gen, fsys)
+
+	want := markdown(
+		"<!-- au:table text -->",
+		"",
+		"| input       | output |",
+		"| ----------- | ------ |",
+		"| a<br>b      | &lt;br>a<br>b&lt;br> |",
+		"| &amp;lt;br> | &lt;br>&amp;lt;br>&lt;br> |",
+	)
+
+	got := blessed["tests/README.md"]
+	if got != want {
+		t.Fatalf("unexpected blessed content:\n%s", got)
+	}
+
+	// The blessed content must match the output when it is loaded again.
+	fsys["tests/README.md"] = &fstest.MapFile{Data: []byte(got)}
+
+	aureus.Run(
+		t,
+		gen,
+		aureus.FromFS(fsys, "tests"),
+	)
+}
+
+func TestRun_cases(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"golden/existing.json": "{\n  \"a\": 1\n}\n",
+	})
+
+	blessed := runBlessed(
+		t,
+		prettyPrint,
+		fsys,
+		aureus.Cases(
+			aureus.Case{
+				Name:          "existing golden file",
+				Input:         []byte(`{"a":1}`),
+				InputLanguage: "json",
+				Golden:        "golden/existing.json",
+			},
+			aureus.Case{
+				Name:          "missing golden file",
+				Input:         []byte(`{"b":2}`),
+				InputLanguage: "json",
+				Golden:        "golden/missing.json",
+			},
+		),
+	)
+
+	if len(blessed) != 1 {
+		t.Fatalf("expected exactly one file to be blessed, got %d", len(blessed))
+	}
+
+	want := "{\n  \"b\": 2\n}\n"
+	if got := blessed["tests/golden/missing.json"]; got != want {
+		t.Fatalf("unexpected blessed content:\n%s", got)
+	}
+}
+
+type pathVisitor []string
+
+func (v *pathVisitor) VisitTest(p []string, _ aureus.Test) error {
+	*v = append(*v, "test: "+strings.Join(p, "/"))
+	return nil
+}
+
+func (v *pathVisitor) VisitAssertion(p []string, a aureus.Assertion) error {
+	*v = append(*v, fmt.Sprintf("assertion: %s (%s -> %s)", strings.Join(p, "/"), a.Input.Language, a.Output.Language))
+	return nil
+}
+
+func TestLoad(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"a/pretty.input.json":  `{"a":1}`,
+		"a/pretty.output.json": "{\n  \"a\": 1\n}\n",
+	})
+
+	suite, err := aureus.Load(aureus.FromFS(fsys, "tests"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v pathVisitor
+	if err := suite.Walk(&v, aureus.WithT(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join(
+		[]string{
+			"test: tests",
+			"test: tests/a",
+			"test: tests/a/pretty",
+			"assertion: tests/a/pretty (json -> json)",
+		},
+		"\n",
+	)
+
+	if got := strings.Join(v, "\n"); got != want {
+		t.Fatalf("unexpected visits:\n%s", got)
+	}
+}
+
+func TestRun_metadata(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"README.md": markdown(
+			"# Title",
+			"",
+			"## Section",
+			"",
+			"### Example",
+			"",
+			"```text au:input au:group=example",
+			"input",
+			"```",
+			"",
+			"```text au:output au:group=example",
+			"location = tests/README.md:7",
+			"group = example",
+			"caption = Example",
+			"headings = Title / Section / Example",
+			"test = TestRun_metadata/tests/Title/example",
+			"```",
+		),
+	})
+
+	aureus.Run(
+		t,
+		func(t *testing.T, in aureus.Input, out aureus.Output) error {
+			_, err := fmt.Fprintf(
+				out,
+				"location = %s\ngroup = %s\ncaption = %s\nheadings = %s\ntest = %s\n",
+				in.Location(),
+				in.Group(),
+				in.Caption(),
+				strings.Join(in.Headings(), " / "),
+				in.TestName(),
+			)
+			return err
+		},
+		aureus.FromFS(fsys, "tests"),
+	)
+}
+
+func TestLoad_attributeSchema(t *testing.T) {
+	schema := aureus.AttributeSchema(
+		aureus.IntAttribute("indent"),
+		aureus.BoolAttribute("sorted"),
+		aureus.EnumAttribute("format", "compact", "pretty"),
+	)
+
+	cases := []struct {
+		Name    string
+		Info    string
+		Extra   string
+		WantErr string
+	}{
+		{
+			Name: "valid attributes",
+			Info: `json au:input indent=2 sorted format=pretty`,
+		},
+		{
+			Name:    "unrecognized attribute",
+			Info:    `json au:input colour=blue`,
+			WantErr: `tests/README.md:1: unrecognized attribute "colour"`,
+		},
+		{
+			Name:    "invalid integer",
+			Info:    `json au:input indent=two`,
+			WantErr: `tests/README.md:1: "indent" attribute: "two" is not an integer`,
+		},
+		{
+			Name:    "invalid boolean",
+			Info:    `json au:input sorted=maybe`,
+			WantErr: `tests/README.md:1: "sorted" attribute: "maybe" is not a boolean`,
+		},
+		{
+			Name:    "invalid enum",
+			Info:    `json au:input format=yaml`,
+			WantErr: `tests/README.md:1: "format" attribute: "yaml" is not one of "compact", "pretty"`,
+		},
+		{
+			Name: "named input",
+			Info: `json au:input`,
+			Extra: markdown(
+				"```json au:input=old au:group=named",
+				"{}",
+				"```",
+				"",
+				"```json au:input=new au:group=named colour=blue",
+				"{}",
+				"```",
+				"",
+				"```json au:output au:group=named",
+				"{}",
+				"```",
+			),
+			WantErr: `tests/README.md:13: unrecognized attribute "colour"`,
+		},
+		{
+			Name: "fixture",
+			Info: `json au:input`,
+			Extra: markdown(
+				"```json au:fixture colour=blue",
+				"{}",
+				"```",
+			),
+			WantErr: `tests/README.md:9: unrecognized attribute "colour"`,
+		},
+		{
+			Name: "round-trip",
+			Info: `json au:input`,
+			Extra: markdown(
+				"```json au:input au:group=round-trip",
+				"{}",
+				"```",
+				"",
+				"```json au:output au:group=round-trip",
+				"{}",
+				"```",
+				"",
+				"```json au:roundtrip au:group=round-trip colour=blue",
+				"{}",
+				"```",
+			),
+			WantErr: `tests/README.md:17: unrecognized attribute "colour"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			fsys := testFS(map[string]string{
+				"README.md": markdown(
+					"```"+c.Info,
+					"{}",
+					"```",
+					"",
+					"```json au:output",
+					"{}",
+					"```",
+					"",
+				) + c.Extra,
+			})
+
+			_, err := aureus.Load(aureus.FromFS(fsys, "tests"), schema)
+
+			if c.WantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+			} else if err == nil || err.Error() != c.WantErr {
+				t.Fatalf("unexpected error: got %v, want %q", err, c.WantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_attributeSchemaCases(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"colourful.json": "{}\n",
+	})
+
+	_, err := aureus.Load(
+		aureus.FromFS(fsys, "tests"),
+		aureus.AttributeSchema(aureus.IntAttribute("indent")),
+		aureus.Cases(
+			aureus.Case{
+				Name:       "colourful",
+				Input:      []byte("{}"),
+				Golden:     "colourful.json",
+				Attributes: map[string]string{"colour": "blue"},
+			},
+		),
+	)
+
+	want := `case "colourful": unrecognized attribute "colour"`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
+
+func TestMux(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"pretty.input.json":           `{"a":1}`,
+		"pretty.output.json":          "{\n  \"a\": 1\n}\n",
+		"upper.input.@mode=upper.txt": "hello\n",
+		"upper.output.txt":            "HELLO\n",
+		"other.input.yaml":            "a: 1\n",
+		"other.output.yaml":           "a: 1\n",
+	})
+
+	var handled []string
+	mux := aureus.NewMux[*testing.T]()
+
+	mux.HandleLanguages(
+		"json", "json",
+		func(t *testing.T, in aureus.Input, out aureus.Output) error {
+			handled = append(handled, "json:"+in.Group())
+			return prettyPrint(t, in, out)
+		},
+	)
+	mux.HandleAttr(
+		"mode", "upper",
+		func(t *testing.T, in aureus.Input, out aureus.Output) error {
+			handled = append(handled, "upper:"+in.Group())
+			return upperCase(t, in, out)
+		},
+	)
+	mux.SkipUnhandled(true)
+
+	aureus.Run(
+		t,
+		mux.Generate,
+		aureus.FromFS(fsys, "tests"),
+	)
+
+	slices.Sort(handled)
+
+	if got, want := strings.Join(handled, ","), "json:pretty,upper:upper"; got != want {
+		t.Fatalf("unexpected handlers: got %q, want %q", got, want)
+	}
+}
+
+func TestFunc(t *testing.T) {
+	type person struct {
+		Name string `json:"name" xml:"name"`
+		Age  int    `json:"age" xml:"age"`
+	}
+
+	fsys := testFS(map[string]string{
+		"person.input.json": `{"name":"Alice","age":30}`,
+		"person.output.xml": "<person>\n" +
+			"  <name>ALICE</name>\n" +
+			"  <age>31</age>\n" +
+			"</person>\n",
+		"greeting.input.txt":  "Bob",
+		"greeting.output.txt": "Hello, Bob!\n",
+	})
+
+	var mux aureus.Mux[*testing.T]
+
+	mux.HandleLanguages(
+		"json", "xml",
+		aureus.Func[*testing.T](
+			func(p person) (person, error) {
+				p.Name = strings.ToUpper(p.Name)
+				p.Age++
+				return p, nil
+			},
+		),
+	)
+
+	mux.HandleLanguages(
+		"txt", "txt",
+		aureus.Func[*testing.T](
+			func(name string) (string, error) {
+				return "Hello, " + name + "!\n", nil
+			},
+		),
+	)
+
+	aureus.Run(
+		t,
+		mux.Generate,
+		aureus.FromFS(fsys, "tests"),
+	)
+}
+
+func TestExec(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	fsys := testFS(map[string]string{
+		"README.md": markdown(
+			"```text au:input au:group=greet name=Alice",
+			"hello",
+			"```",
+			"",
+			"```text au:output au:group=greet",
+			"hello",
+			"Alice",
+			"```",
+			"",
+			"```text au:output=stderr au:group=greet",
+			"greeting ALICE",
+			"```",
+			"",
+			"```text au:output=exit-code au:group=greet",
+			"3",
+			"```",
+		),
+	})
+
+	// Record each execution of the program, so that we can verify that it is
+	// run once for all three outputs.
+	log := path.Join(t.TempDir(), "runs.log")
+
+	aureus.Run(
+		t,
+		aureus.Exec[*testing.T](
+			"sh",
+			"-c",
+			`echo run >> "$2"; cat; echo "$1"; echo "greeting $AUREUS_NAME" | tr a-z A-Z | sed 's/GREETING/greeting/' >&2; exit 3`,
+			"sh",
+			"{{.name}}",
+			log,
+		),
+		aureus.FromFS(fsys, "tests"),
+	)
+
+	runs, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := string(runs), "run\n"; got != want {
+		t.Fatalf("unexpected number of runs:\n%s", got)
+	}
+}
+
+func TestExec_multipleInputsAndOutputs(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	fsys := testFS(map[string]string{
+		"README.md": markdown(
+			"```text au:input au:group=count",
+			"one",
+			"```",
+			"",
+			"```text au:input au:group=count",
+			"two",
+			"```",
+			"",
+			"```text au:output=stdout au:group=count",
+			"ok",
+			"```",
+			"",
+			"```text au:output=exit-code au:group=count",
+			"0",
+			"```",
+		),
+	})
+
+	// The assertions are made for each output in turn, so the program must be
+	// run once for each input, not once for each assertion.
+	log := path.Join(t.TempDir(), "runs.log")
+
+	aureus.Run(
+		t,
+		aureus.Exec[*testing.T](
+			"sh",
+			"-c",
+			`cat >> "$1"; echo ok`,
+			"sh",
+			log,
+		),
+		aureus.FromFS(fsys, "tests"),
+	)
+
+	runs, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := string(runs), "one\ntwo\n"; got != want {
+		t.Fatalf("unexpected runs:\n%s", got)
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"README.md": markdown(
+			"```http au:input au:group=echo",
+			"POST /echo?greeting=hello HTTP/1.1",
+			"Host: example.org",
+			"",
+			`{"name":"Alice"}`,
+			"```",
+			"",
+			"```http au:output au:group=echo",
+			"HTTP/1.1 201 Created",
+			"Content-Type: application/json",
+			"X-Greeting: hello",
+			"",
+			"{",
+			`  "name": "Alice"`,
+			"}",
+			"```",
+			"",
+			"```http au:input au:group=not-found",
+			"GET /missing HTTP/1.1",
+			"Host: example.org",
+			"```",
+			"",
+			"```http au:output au:group=not-found",
+			"HTTP/1.1 404 Not Found",
+			"```",
+		),
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(
+		"POST /echo",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Date", time.Now().Format(http.TimeFormat))
+			w.Header().Set("X-Greeting", r.URL.Query().Get("greeting"))
+			w.WriteHeader(http.StatusCreated)
+			io.Copy(w, r.Body)
+		},
+	)
+	mux.HandleFunc(
+		"/",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		},
+	)
+
+	aureus.Run(
+		t,
+		aureus.HTTPHandler[*testing.T](
+			mux,
+			aureus.HTTPExcludeHeaders("date"),
+			aureus.HTTPIndentJSON(true),
+		),
+		aureus.FromFS(fsys, "tests"),
+	)
+}
+
+func TestSession(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	fsys := testFS(map[string]string{
+		"README.md": markdown(
+			"```console au:session",
+			"$ echo hello > greeting.txt",
+			"$ cat greeting.txt",
+			"<incorrect>",
+			"$ ls",
+			"greeting.txt",
+			"```",
+		),
+	})
+
+	// Record each command that is executed, so that we can verify that each
+	// command is only run once.
+	log := path.Join(t.TempDir(), "commands.log")
+	shell := []string{"sh", "-c", `printf '%s\n' "$0" >> '` + log + `'; eval "$0"`}
+
+	blessed := runBlessed(t, aureus.Session[*testing.T](shell...), fsys)
+
+	want := markdown(
+		"```console au:session",
+		"$ echo hello > greeting.txt",
+		"$ cat greeting.txt",
+		"hello",
+		"$ ls",
+		"greeting.txt",
+		"```",
+	)
+
+	if got := blessed["tests/README.md"]; got != want {
+		t.Fatalf("unexpected blessed content:\n%s", got)
+	}
+
+	commands, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := string(commands), "echo hello > greeting.txt\ncat greeting.txt\nls\n"; got != want {
+		t.Fatalf("unexpected commands:\n%s", got)
+	}
+}
+
+func TestRun_fixtures(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"fixture=greeting.txt":  "Hello",
+		"alice.input.txt":       "Alice",
+		"alice.output.txt":      "Hello, Alice!\n",
+		"bob.fixture=punct.txt": "?",
+		"bob.input.txt":         "Bob",
+		"bob.output.txt":        "Hello, Bob?\n",
+	})
+
+	aureus.Run(
+		t,
+		func(t *testing.T, in aureus.Input, out aureus.Output) error {
+			name, err := io.ReadAll(in)
+			if err != nil {
+				return err
+			}
+
+			fixtures := map[string]string{"punct": "!"}
+			for _, f := range in.Fixtures() {
+				fixtures[f.Name] = string(f.Data)
+			}
+
+			_, err = fmt.Fprintf(out, "%s, %s%s\n", fixtures["greeting"], name, fixtures["punct"])
+			return err
+		},
+		aureus.FromFS(fsys, "tests"),
+	)
+}
+
+func TestRun_namedInputs(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"migration.input=old.txt": "one\ntwo\n",
+		"migration.input=new.txt": "one\nthree\n",
+		"migration.output.txt":    "-two\n+three\n",
+	})
+
+	aureus.Run(
+		t,
+		func(t *testing.T, in aureus.Input, out aureus.Output) error {
+			lines := map[string][]string{}
+			for name, x := range in.NamedInputs() {
+				data, err := io.ReadAll(x)
+				if err != nil {
+					return err
+				}
+				lines[name] = strings.Fields(string(data))
+			}
+
+			if _, ok := in.Named("new"); !ok {
+				t.Fatal("expected a named input called 'new'")
+			}
+
+			for _, l := range lines["old"] {
+				if !slices.Contains(lines["new"], l) {
+					fmt.Fprintf(out, "-%s\n", l)
+				}
+			}
+
+			for _, l := range lines["new"] {
+				if !slices.Contains(lines["old"], l) {
+					fmt.Fprintf(out, "+%s\n", l)
+				}
+			}
+
+			return nil
+		},
+		aureus.FromFS(fsys, "tests"),
+	)
+}
+
+func TestRun_pipeline(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"README.md": markdown(
+			"```text au:input au:group=shout",
+			"hello",
+			"```",
+			"",
+			"```text au:output au:group=shout au:stage=upper",
+			"<incorrect>",
+			"```",
+			"",
+			"```text au:output au:group=shout au:stage=exclaim",
+			"HELLO!",
+			"```",
+		),
+	})
+
+	var stages []string
+
+	runBlessed(
+		t,
+		func(t *testing.T, in aureus.Input, out aureus.Output) error {
+			stages = append(stages, out.Stage())
+
+			data, err := io.ReadAll(in)
+			if err != nil {
+				return err
+			}
+			data = bytes.TrimSpace(data)
+
+			switch out.Stage() {
+			case "upper":
+				data = bytes.ToUpper(data)
+			case "exclaim":
+				data = append(data, '!')
+			}
+
+			_, err = fmt.Fprintf(out, "%s\n", data)
+			return err
+		},
+		fsys,
+	)
+
+	if got, want := strings.Join(stages, ","), "upper,exclaim"; got != want {
+		t.Fatalf("unexpected stages: got %q, want %q", got, want)
+	}
+}
+
+func TestRun_pipelineSkippedStage(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"README.md": markdown(
+			"```text au:input au:group=shout",
+			"hello",
+			"```",
+			"",
+			"```text au:output au:group=shout au:stage=upper au:skip",
+			"HELLO",
+			"```",
+			"",
+			"```text au:output au:group=shout au:stage=exclaim",
+			"HELLO!",
+			"```",
+		),
+	})
+
+	var inputs []string
+
+	aureus.Run(
+		t,
+		func(t *testing.T, in aureus.Input, out aureus.Output) error {
+			data, err := io.ReadAll(in)
+			if err != nil {
+				return err
+			}
+			data = bytes.TrimSpace(data)
+			inputs = append(inputs, out.Stage()+":"+string(data))
+
+			_, err = fmt.Fprintf(out, "%s!\n", data)
+			return err
+		},
+		aureus.FromFS(fsys, "tests"),
+	)
+
+	if got, want := strings.Join(inputs, ","), "exclaim:HELLO"; got != want {
+		t.Fatalf("unexpected stage inputs: got %q, want %q", got, want)
+	}
+}
+
+func TestRun_roundTrip(t *testing.T) {
+	fsys := testFS(map[string]string{
+		"README.md": markdown(
+			"```text au:input au:group=implicit",
+			"hello",
+			"```",
+			"",
+			"```text au:output au:group=implicit",
+			"HELLO",
+			"```",
+			"",
+			"```text au:input au:group=explicit",
+			"Hello",
+			"```",
+			"",
+			"```text au:output au:group=explicit",
+			"HELLO",
+			"```",
+			"",
+			"```text au:roundtrip au:group=explicit",
+			"hello",
+			"```",
+		),
+	})
+
+	var inputs []string
+
+	aureus.Run(
+		t,
+		aureus.RoundTrip(
+			upperCase,
+			func(t *testing.T, in aureus.Input, out aureus.Output) error {
+				data, err := io.ReadAll(in)
+				if err != nil {
+					return err
+				}
+				inputs = append(inputs, strings.TrimSpace(string(data)))
+				_, err = out.Write(bytes.ToLower(data))
+				return err
+			},
+		),
+		aureus.FromFS(fsys, "tests"),
+	)
+
+	if got, want := strings.Join(inputs, ","), "HELLO,HELLO"; got != want {
+		t.Fatalf("unexpected round-trip inputs: got %q, want %q", got, want)
+	}
+}
+
+func TestRoundTrip_wrappedOutput(t *testing.T) {
+	type wrapped struct{ aureus.Output }
+
+	gen := aureus.RoundTrip(prettyPrint, prettyPrint)
+
+	if err := gen(t, nil, wrapped{}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
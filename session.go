@@ -0,0 +1,118 @@
+package aureus
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+)
+
+// Session returns an [OutputGenerator] that replays the commands of a shell
+// session, such as those loaded from an "au:session" block in a Markdown
+// document.
+//
+// Each line of the input is a command. The commands are executed once, in
+// order, within a temporary working directory that is shared by all of the
+// commands. The exit code of each command is ignored.
+//
+// The output is the combined standard output and standard error of a single
+// command. The name of the expected output is the 1-based position of the
+// command within the session, or if the output is un-named, the last command.
+// Each step of a session loaded from a Markdown document shares the result of
+// a single run of the session.
+//
+// shell is the program and arguments used to execute each command, which is
+// passed as the final argument. If it is empty, commands are executed using
+// "sh -c".
+func Session[T TestingT[T]](shell ...string) OutputGenerator[T] {
+	if len(shell) == 0 {
+		shell = []string{"sh", "-c"}
+	}
+
+	var cache resultCache[[][]byte]
+
+	return func(t T, in Input, out Output) error {
+		t.Helper()
+
+		data, err := io.ReadAll(in)
+		if err != nil {
+			return err
+		}
+
+		outputs, err := cache.get(
+			inputKey(in.Location(), in.Attributes(), data),
+			out.Name(),
+			func() ([][]byte, error) {
+				return runSession(shell, in.Attributes(), data)
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		if len(outputs) == 0 {
+			return nil
+		}
+
+		i := len(outputs)
+		if out.Name() != "" {
+			i, err = strconv.Atoi(out.Name())
+			if err != nil || i < 1 || i > len(outputs) {
+				return fmt.Errorf(
+					"unrecognized output name %q, expected a command number between 1 and %d",
+					out.Name(),
+					len(outputs),
+				)
+			}
+		}
+
+		_, err = out.Write(outputs[i-1])
+		return err
+	}
+}
+
+// runSession runs each line of commands as a separate command, and returns the
+// combined output of each command.
+func runSession(shell []string, attrs Attributes, commands []byte) ([][]byte, error) {
+	dir, err := os.MkdirTemp("", "aureus-session-")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create temporary working directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	env := os.Environ()
+	for _, k := range slices.Sorted(maps.Keys(attrs)) {
+		env = append(env, attributeEnvName(k)+"="+attrs[k])
+	}
+
+	var outputs [][]byte
+
+	lines := bufio.NewScanner(bytes.NewReader(commands))
+	for lines.Scan() {
+		var output bytes.Buffer
+
+		args := append(slices.Clone(shell[1:]), lines.Text())
+		cmd := exec.Command(shell[0], args...)
+		cmd.Dir = dir
+		cmd.Env = env
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+
+		if err := cmd.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				return nil, fmt.Errorf("unable to run %q: %w", lines.Text(), err)
+			}
+		}
+
+		outputs = append(outputs, output.Bytes())
+	}
+
+	return outputs, lines.Err()
+}
@@ -0,0 +1,66 @@
+package aureus
+
+import (
+	"github.com/dogmatiq/aureus/internal/test"
+)
+
+// Suite is a collection of tests loaded by [Load].
+type Suite struct {
+	tests []test.Test
+}
+
+// Load searches a directory for tests and returns them as a [Suite], without
+// executing them.
+//
+// It accepts the same options as [Run], and loads the same tests. Options that
+// only affect test execution are ignored. It allows tests discovered by Aureus
+// to be inspected, or executed by other test frameworks.
+func Load(options ...RunOption) (*Suite, error) {
+	opts := newRunOptions(options)
+
+	tests, err := loadTests(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Suite{tests}, nil
+}
+
+// Tests returns the top-level tests in the suite.
+func (s *Suite) Tests() []Test {
+	return s.tests
+}
+
+// Walk visits each test in the suite, and each of the assertions within those
+// tests, in depth-first order.
+//
+// It stops at the first error returned by v, other than [SkipTest], and
+// returns that error.
+func (s *Suite) Walk(v Visitor, options ...VisitOption) error {
+	return test.Walk(s.tests, v, options...)
+}
+
+// Visitor is an interface for visiting the tests and assertions within a
+// [Suite].
+//
+// VisitTest is called for each test. path contains the names of the test's
+// ancestors, followed by the name of the test itself. If it returns [SkipTest]
+// the test's sub-tests and assertions are not visited.
+//
+// VisitAssertion is called for each assertion, where path is the path of the
+// test that contains the assertion.
+type Visitor = test.Visitor
+
+// SkipTest is a special error that may be returned by a [Visitor] to skip the
+// sub-tests and assertions of a test.
+var SkipTest = test.SkipTest
+
+// VisitOption is an option that changes the behavior of [Suite.Walk].
+type VisitOption = test.VisitOption
+
+// WithT is a [VisitOption] that marks the internals of [Suite.Walk] as test
+// helpers of t, such that failures reported by a [Visitor] are attributed to
+// the caller.
+func WithT(t interface{ Helper() }) VisitOption {
+	return test.WithT(t)
+}